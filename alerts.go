@@ -0,0 +1,558 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Alert rules come from [[alerts.rule]] tables in the config file. A
+// process rule fires for each process that matches its filter query on
+// every sample for at least its duration; a system rule compares a system
+// metric with a threshold. Firing and resolving are both transitions: each
+// is added to the alert log, appended to the log file and passed to the
+// exec hook.
+
+const (
+	alertLogMax      = 500 // transitions kept for the alert log view
+	alertHookTimeout = 30 * time.Second
+	alertHookMax     = 4 // hooks running at once; the rest wait their turn
+)
+
+// Set from the [alerts] section of the config file.
+var (
+	alertRules []*alertRule
+	alertExec  string // run on every transition unless the rule has its own
+	alertLog   string // file every transition is appended to; "" for none
+)
+
+// alertRuleConfig mirrors one [[alerts.rule]] table.
+type alertRuleConfig struct {
+	Name   string        `toml:"name"`
+	Match  string        `toml:"match"`
+	System string        `toml:"system"`
+	For    time.Duration `toml:"for"`
+	Exec   string        `toml:"exec"`
+}
+
+type alertRule struct {
+	name  string
+	cond  string        // the condition as written in the config
+	query *query        // process rule
+	sys   *sysCondition // system rule
+	dur   time.Duration // how long the condition must hold before firing
+	exec  string
+}
+
+// newAlertRule validates one rule from the config file.
+func newAlertRule(c alertRuleConfig) (*alertRule, error) {
+	r := &alertRule{name: c.Name, dur: c.For, exec: c.Exec}
+	switch {
+	case c.Match != "" && c.System != "":
+		return nil, fmt.Errorf("set match or system, not both")
+	case c.Match != "":
+		q, err := parseQuery(c.Match, matchOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("match: %v", err)
+		}
+		r.cond, r.query = c.Match, q
+	case c.System != "":
+		s, err := parseSysCondition(c.System)
+		if err != nil {
+			return nil, fmt.Errorf("system: %v", err)
+		}
+		r.cond, r.sys = c.System, s
+	default:
+		return nil, fmt.Errorf("match or system is required")
+	}
+	if c.For < 0 {
+		return nil, fmt.Errorf("for: %v is negative", c.For)
+	}
+	if r.name == "" {
+		r.name = r.cond
+	}
+	return r, nil
+}
+
+// sysCondition compares a system metric with a threshold, e.g. avail<5.
+type sysCondition struct {
+	metric string
+	op     string
+	val    float64
+}
+
+// sysMetrics are what a system rule can test. Memory and swap are percent
+// of the total; ok is false when the metric isn't available.
+var sysMetrics = map[string]func(s *sysStatsMsg) (v float64, ok bool){
+	"cpu": func(s *sysStatsMsg) (float64, bool) { return s.CPUTotal, true },
+	"mem": func(s *sysStatsMsg) (float64, bool) {
+		return s.MemUsed / s.MemTotal * 100, s.MemTotal > 0
+	},
+	"avail": func(s *sysStatsMsg) (float64, bool) {
+		return s.MemAvail / s.MemTotal * 100, s.MemTotal > 0
+	},
+	"swap": func(s *sysStatsMsg) (float64, bool) {
+		return s.SwapUsed / s.SwapTotal * 100, s.SwapTotal > 0
+	},
+	"load1":  func(s *sysStatsMsg) (float64, bool) { return s.Load1, s.HasLoad },
+	"load5":  func(s *sysStatsMsg) (float64, bool) { return s.Load5, s.HasLoad },
+	"load15": func(s *sysStatsMsg) (float64, bool) { return s.Load15, s.HasLoad },
+}
+
+var sysConditionRe = regexp.MustCompile(`^\s*([a-z0-9]+)\s*(<=|>=|<|>)\s*([0-9.]+)%?\s*$`)
+
+func parseSysCondition(s string) (*sysCondition, error) {
+	m := sysConditionRe.FindStringSubmatch(strings.ToLower(s))
+	if m == nil {
+		return nil, fmt.Errorf("%q: want metric, comparison and number, e.g. avail<5", s)
+	}
+	if _, ok := sysMetrics[m[1]]; !ok {
+		return nil, fmt.Errorf("unknown metric %q (want one of %s)", m[1],
+			strings.Join(sortedKeys(sysMetrics), ", "))
+	}
+	val, err := strconv.ParseFloat(m[3], 64)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a number", m[3])
+	}
+	return &sysCondition{metric: m[1], op: m[2], val: val}, nil
+}
+
+// eval reports whether the condition holds for s, and the measured value.
+func (c *sysCondition) eval(s *sysStatsMsg) (string, bool) {
+	v, ok := sysMetrics[c.metric](s)
+	if !ok {
+		return "", false
+	}
+	unit := "%"
+	if strings.HasPrefix(c.metric, "load") {
+		unit = ""
+	}
+	return fmt.Sprintf("%s %.1f%s", c.metric, v, unit), compareNum(v, c.op, c.val)
+}
+
+// alertEvent is one transition of an alert: it fired, or it resolved.
+type alertEvent struct {
+	At       time.Time
+	Since    time.Time // when the condition started to hold
+	Rule     string
+	Cond     string
+	Resolved bool
+	PID      int32 // 0 for system rules
+	Process  string
+	Value    string // what was measured, e.g. "avail 4.2%"
+	Host     string
+	exec     string
+}
+
+func (e alertEvent) state() string {
+	if e.Resolved {
+		return "resolved"
+	}
+	return "firing"
+}
+
+// message is the one-line description shown in the banner and the log.
+func (e alertEvent) message() string {
+	s := e.Rule + ": "
+	if e.PID != 0 {
+		s += fmt.Sprintf("%s (PID %d) %s", e.Process, e.PID, e.Value)
+	} else {
+		s += e.Value
+	}
+	if e.Resolved {
+		s += fmt.Sprintf(" — resolved after %s", e.At.Sub(e.Since).Round(time.Second))
+	}
+	return s
+}
+
+// alertKey identifies one alert: a system rule, or a process rule and one
+// of the processes it matched.
+type alertKey struct {
+	rule int
+	pid  int32
+}
+
+// alerter tracks alert state across samples. The model holds it by pointer,
+// so the state survives the copies Update makes.
+type alerter struct {
+	rules  []*alertRule
+	fields procField               // optional process fields the rules read
+	since  map[alertKey]time.Time  // condition holding, since when
+	firing map[alertKey]alertEvent // fired and not yet resolved
+	log    []alertEvent            // transitions, oldest first
+}
+
+// newAlerter returns nil when there are no rules.
+func newAlerter(rules []*alertRule) *alerter {
+	if len(rules) == 0 {
+		return nil
+	}
+	a := &alerter{rules: rules}
+	for _, r := range rules {
+		if r.query != nil {
+			a.fields |= r.query.fields
+		}
+	}
+	a.reset()
+	return a
+}
+
+// reset forgets pending and firing alerts but keeps the log.
+func (a *alerter) reset() {
+	a.since = map[alertKey]time.Time{}
+	a.firing = map[alertKey]alertEvent{}
+}
+
+// checkProcs evaluates the process rules against a sample taken at now.
+func (a *alerter) checkProcs(procs []ProcessRow, now time.Time, host string) []alertEvent {
+	var out []alertEvent
+	for i, r := range a.rules {
+		if r.query == nil {
+			continue
+		}
+		holds := map[alertKey]alertEvent{}
+		for j := range procs {
+			p := &procs[j]
+			if !r.query.Match(p) {
+				continue
+			}
+			holds[alertKey{i, p.PID}] = alertEvent{
+				Rule: r.name, Cond: r.cond, PID: p.PID, Process: p.Name, Host: host, exec: r.exec,
+				Value: fmt.Sprintf("CPU %.1f%%, RSS %.0f MB", p.CPU, p.MemMB),
+			}
+		}
+		out = append(out, a.observe(i, holds, now)...)
+	}
+	return out
+}
+
+// checkSys evaluates the system rules against a sample taken at now.
+func (a *alerter) checkSys(s sysStatsMsg, now time.Time) []alertEvent {
+	var out []alertEvent
+	for i, r := range a.rules {
+		if r.sys == nil {
+			continue
+		}
+		holds := map[alertKey]alertEvent{}
+		if v, ok := r.sys.eval(&s); ok {
+			holds[alertKey{rule: i}] = alertEvent{
+				Rule: r.name, Cond: r.cond, Value: v, Host: s.Hostname, exec: r.exec,
+			}
+		}
+		out = append(out, a.observe(i, holds, now)...)
+	}
+	return out
+}
+
+// observe moves one rule's alerts on by a sample: holds has an event for
+// each key whose condition holds now. Alerts fire once their condition has
+// held for the rule's duration and resolve as soon as it stops holding (or
+// the process exits). A firing alert keeps its latest value.
+func (a *alerter) observe(rule int, holds map[alertKey]alertEvent, now time.Time) []alertEvent {
+	dur := a.rules[rule].dur
+	var out []alertEvent
+	for k, e := range holds {
+		since, ok := a.since[k]
+		if !ok {
+			since = now
+			a.since[k] = now
+		}
+		if f, fired := a.firing[k]; fired {
+			e.At, e.Since = f.At, f.Since
+			a.firing[k] = e
+			continue
+		}
+		if now.Sub(since) >= dur {
+			e.At, e.Since = now, since
+			a.firing[k] = e
+			out = append(out, e)
+		}
+	}
+	for k := range a.since {
+		if _, ok := holds[k]; ok || k.rule != rule {
+			continue
+		}
+		delete(a.since, k)
+		if e, fired := a.firing[k]; fired {
+			delete(a.firing, k)
+			e.At, e.Resolved = now, true
+			out = append(out, e)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].PID < out[j].PID })
+	return out
+}
+
+// add appends transitions to the log, dropping the oldest past alertLogMax.
+func (a *alerter) add(events []alertEvent) {
+	a.log = append(a.log, events...)
+	if over := len(a.log) - alertLogMax; over > 0 {
+		a.log = append([]alertEvent(nil), a.log[over:]...)
+	}
+}
+
+// active lists the firing alerts, most recently fired first. A nil alerter
+// has none.
+func (a *alerter) active() []alertEvent {
+	if a == nil {
+		return nil
+	}
+	out := make([]alertEvent, 0, len(a.firing))
+	for _, e := range a.firing {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].At.Equal(out[j].At) {
+			return out[i].At.After(out[j].At)
+		}
+		if out[i].Rule != out[j].Rule {
+			return out[i].Rule < out[j].Rule
+		}
+		return out[i].PID < out[j].PID
+	})
+	return out
+}
+
+// ---------------------------------------------------------------------------
+// Hook and log file
+// ---------------------------------------------------------------------------
+
+// alertHookMsg reports a failed hook or log write; Rule is empty for the
+// log file.
+type alertHookMsg struct {
+	Rule string
+	Err  error
+}
+
+// env describes e to the hook.
+func (e alertEvent) env() []string {
+	pid := ""
+	if e.PID != 0 {
+		pid = strconv.Itoa(int(e.PID))
+	}
+	return []string{
+		"GOMON_ALERT_RULE=" + e.Rule,
+		"GOMON_ALERT_STATE=" + e.state(),
+		"GOMON_ALERT_CONDITION=" + e.Cond,
+		"GOMON_ALERT_MESSAGE=" + e.message(),
+		"GOMON_ALERT_VALUE=" + e.Value,
+		"GOMON_ALERT_PID=" + pid,
+		"GOMON_ALERT_PROCESS=" + e.Process,
+		"GOMON_ALERT_SINCE=" + e.Since.Format(time.RFC3339),
+		"GOMON_ALERT_TIME=" + e.At.Format(time.RFC3339),
+		"GOMON_HOST=" + e.Host,
+	}
+}
+
+// alertHookSlots bounds the hooks running at once, so a broad rule that
+// fires for hundreds of processes on one sample doesn't fork as many shells.
+var alertHookSlots = make(chan struct{}, alertHookMax)
+
+// runAlertHook runs command through the shell with e in its environment,
+// once one of alertHookSlots is free. The terminal belongs to the TUI, so
+// the hook gets no input and its output is discarded; the first line of
+// stderr is kept for the error.
+func runAlertHook(command string, e alertEvent) error {
+	alertHookSlots <- struct{}{}
+	defer func() { <-alertHookSlots }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), alertHookTimeout)
+	defer cancel()
+	cmd := shellCommand(ctx, command)
+	cmd.Env = append(os.Environ(), e.env()...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		return fmt.Errorf("timed out after %v", alertHookTimeout)
+	}
+	if err != nil {
+		if line, _, _ := strings.Cut(strings.TrimSpace(stderr.String()), "\n"); line != "" {
+			return fmt.Errorf("%v: %s", err, line)
+		}
+	}
+	return err
+}
+
+func appendAlertLog(path string, events []alertEvent) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	for _, e := range events {
+		fmt.Fprintf(f, "%s %-8s %s\n", e.At.Format(time.RFC3339), e.state(), e.message())
+	}
+	return f.Close()
+}
+
+// ---------------------------------------------------------------------------
+// Model glue
+// ---------------------------------------------------------------------------
+
+// sampleTime is when the sample being handled was taken: now, or the frame
+// time when replaying.
+func (m *Model) sampleTime() time.Time {
+	if m.replay != nil {
+		return m.replay.frameTime()
+	}
+	return time.Now()
+}
+
+func (m *Model) checkProcAlerts(procs []ProcessRow) tea.Cmd {
+	if m.alerts == nil {
+		return nil
+	}
+	return m.alertCmd(m.alerts.checkProcs(procs, m.sampleTime(), m.sysStats.Hostname))
+}
+
+func (m *Model) checkSysAlerts(s sysStatsMsg) tea.Cmd {
+	if m.alerts == nil {
+		return nil
+	}
+	return m.alertCmd(m.alerts.checkSys(s, m.sampleTime()))
+}
+
+// alertCmd logs transitions and returns the commands that write the log
+// file and run the hooks. A replay shows its alerts but runs no hooks and
+// writes no log: nothing is happening now.
+func (m *Model) alertCmd(events []alertEvent) tea.Cmd {
+	if len(events) == 0 {
+		return nil
+	}
+	m.alerts.add(events)
+	if m.replay != nil {
+		return nil
+	}
+	var cmds []tea.Cmd
+	if path := alertLog; path != "" {
+		cmds = append(cmds, func() tea.Msg {
+			return alertHookMsg{Err: appendAlertLog(path, events)}
+		})
+	}
+	for _, e := range events {
+		hook := e.exec
+		if hook == "" {
+			hook = alertExec
+		}
+		if hook == "" {
+			continue
+		}
+		e := e
+		cmds = append(cmds, func() tea.Msg {
+			return alertHookMsg{Rule: e.Rule, Err: runAlertHook(hook, e)}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) handleAlertHookMsg(msg alertHookMsg) {
+	switch {
+	case msg.Err == nil:
+	case msg.Rule == "":
+		m.statusMsg = fmt.Sprintf("alert log %s: %v", alertLog, msg.Err)
+	default:
+		m.statusMsg = fmt.Sprintf("alert hook for %q failed: %v", msg.Rule, msg.Err)
+	}
+}
+
+// renderAlertBadge is the status bar's count of firing alerts.
+func (m *Model) renderAlertBadge() string {
+	n := len(m.alerts.active())
+	if n == 0 {
+		return ""
+	}
+	plural := "s"
+	if n == 1 {
+		plural = ""
+	}
+	return styleAlertBadge.Render(fmt.Sprintf(" ⚠ %d alert%s ", n, plural))
+}
+
+func (m Model) handleAlertsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case keyEsc, keyQuit, keyAlerts:
+		m.mode = ModeNormal
+	case keyUp, keyVimUp:
+		scrollBody(&m.alertScroll, -1, len(m.alertLines()), m.bodyHeight(pageChrome))
+	case keyDown, keyVimDown:
+		scrollBody(&m.alertScroll, 1, len(m.alertLines()), m.bodyHeight(pageChrome))
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// ---------------------------------------------------------------------------
+// View
+// ---------------------------------------------------------------------------
+
+// alertLines lists the firing alerts, the log (newest first) and the rules.
+func (m *Model) alertLines() []string {
+	var lines []string
+	add := func(s string) {
+		lines = append(lines, "  "+truncate(s, m.termWidth-2))
+	}
+	heading := func(s string) {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, styleHelpSection.Render("  "+truncate(s, m.termWidth-2)))
+	}
+
+	heading("Firing")
+	active := m.alerts.active()
+	if len(active) == 0 {
+		add("none")
+	}
+	for _, e := range active {
+		add(fmt.Sprintf("since %s  %s", e.Since.Format("15:04:05"), e.message()))
+	}
+
+	heading("Log")
+	if len(m.alerts.log) == 0 {
+		add("no alerts yet")
+	}
+	for i := len(m.alerts.log) - 1; i >= 0; i-- {
+		e := m.alerts.log[i]
+		add(fmt.Sprintf("%s  %-8s  %s", e.At.Format("15:04:05"), e.state(), e.message()))
+	}
+
+	heading("Rules")
+	for _, r := range m.alerts.rules {
+		kind := "process"
+		if r.sys != nil {
+			kind = "system"
+		}
+		add(fmt.Sprintf("%-20s %-7s  %s  for %v", truncate(r.name, 20), kind, r.cond, r.dur))
+	}
+	return lines
+}
+
+func (m *Model) renderAlertsScreen() string {
+	var b strings.Builder
+	indent := "  "
+
+	b.WriteString(m.renderHeader())
+	b.WriteString("\n")
+	b.WriteString(m.renderSeparator())
+	b.WriteString("\n")
+	b.WriteString(styleHelpTitle.Render(fmt.Sprintf("%sAlerts — %d firing", indent, len(m.alerts.active()))))
+	b.WriteString("\n")
+
+	b.WriteString(renderScrollBody(m.alertLines(), &m.alertScroll, m.bodyHeight(pageChrome)))
+
+	b.WriteString(m.renderSeparator())
+	b.WriteString("\n")
+	b.WriteString(styleStatusBar.Render(indent + scrollHelp() + "  ·  " +
+		keysLabel(keyEsc, keyQuit, keyAlerts) + " back"))
+
+	return b.String()
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func mustAlertRule(t *testing.T, c alertRuleConfig) *alertRule {
+	t.Helper()
+	r, err := newAlertRule(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestAlerterTransitions(t *testing.T) {
+	a := newAlerter([]*alertRule{
+		mustAlertRule(t, alertRuleConfig{Name: "hot", Match: "name:java cpu>90", For: 30 * time.Second}),
+		mustAlertRule(t, alertRuleConfig{Name: "low memory", System: "avail<5"}),
+	})
+	t0 := time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC)
+	hot := []ProcessRow{{PID: 7, Name: "java", CPU: 95}, {PID: 8, Name: "javac", CPU: 99}}
+	cool := []ProcessRow{{PID: 7, Name: "java", CPU: 10}, {PID: 8, Name: "javac", CPU: 99}}
+
+	// Both match from t0; neither fires until it has held for 30s.
+	if ev := a.checkProcs(hot, t0, "h"); len(ev) != 0 {
+		t.Fatalf("fired at once: %+v", ev)
+	}
+	if ev := a.checkProcs(hot, t0.Add(20*time.Second), "h"); len(ev) != 0 {
+		t.Fatalf("fired after 20s: %+v", ev)
+	}
+	ev := a.checkProcs(hot, t0.Add(30*time.Second), "h")
+	if len(ev) != 2 || ev[0].PID != 7 || ev[0].Resolved || !ev[0].Since.Equal(t0) {
+		t.Fatalf("after 30s: %+v, want PIDs 7 and 8 firing since t0", ev)
+	}
+	if ev := a.checkProcs(hot, t0.Add(40*time.Second), "h"); len(ev) != 0 {
+		t.Errorf("fired again while still firing: %+v", ev)
+	}
+
+	ev = a.checkProcs(cool, t0.Add(50*time.Second), "h")
+	if len(ev) != 1 || ev[0].PID != 7 || !ev[0].Resolved {
+		t.Fatalf("after cooling: %+v, want PID 7 resolved", ev)
+	}
+	if got := ev[0].message(); got != "hot: java (PID 7) CPU 95.0%, RSS 0 MB — resolved after 50s" {
+		t.Errorf("message = %q", got)
+	}
+	if n := len(a.active()); n != 1 {
+		t.Errorf("%d alerts active, want 1", n)
+	}
+
+	// A system rule with no duration fires on the first sample that holds.
+	low := goldenSys
+	low.MemAvail = 0.5
+	ev = a.checkSys(low, t0)
+	if len(ev) != 1 || ev[0].Value != "avail 3.1%" {
+		t.Fatalf("low memory: %+v", ev)
+	}
+	if ev := a.checkSys(goldenSys, t0.Add(time.Second)); len(ev) != 1 || !ev[0].Resolved {
+		t.Errorf("memory recovered: %+v, want resolved", ev)
+	}
+}
+
+func TestAlertRuleConfig(t *testing.T) {
+	for _, c := range []alertRuleConfig{
+		{},
+		{Match: "cpu>90", System: "avail<5"},
+		{Match: "cpu>>90"},
+		{System: "disk>90"},
+		{System: "avail is low"},
+		{System: "avail<5", For: -time.Second},
+	} {
+		if _, err := newAlertRule(c); err == nil {
+			t.Errorf("%+v: no error", c)
+		}
+	}
+}
+
+func TestAlertHookEnvironment(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook test uses /bin/sh")
+	}
+	out := filepath.Join(t.TempDir(), "env")
+	e := alertEvent{
+		At: time.Date(2024, 3, 1, 3, 0, 30, 0, time.UTC), Since: time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC),
+		Rule: "hot", Cond: "cpu>90", PID: 7, Process: "java", Value: "CPU 95.0%, RSS 0 MB", Host: "h",
+	}
+	if err := runAlertHook(`env | grep ^GOMON_ | sort > `+out, e); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"GOMON_ALERT_RULE=hot", "GOMON_ALERT_STATE=firing", "GOMON_ALERT_PID=7",
+		"GOMON_ALERT_PROCESS=java", "GOMON_ALERT_TIME=2024-03-01T03:00:30Z", "GOMON_HOST=h",
+	} {
+		if !strings.Contains(string(got), want+"\n") {
+			t.Errorf("hook environment lacks %s:\n%s", want, got)
+		}
+	}
+
+	if err := runAlertHook("echo broken >&2; exit 3", e); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("failing hook: err = %v, want its stderr", err)
+	}
+}
+
+func TestAlertHookBurst(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook test uses /bin/sh")
+	}
+	// Each hook counts the hooks running alongside it.
+	dir := t.TempDir()
+	defer func(exec string) { alertExec = exec }(alertExec)
+	alertExec = "d=" + dir + "/running.$GOMON_ALERT_PID; mkdir $d; ls -d " + dir + "/running.* | wc -l >> " +
+		dir + "/counts; sleep 0.05; rmdir $d"
+
+	// One broad rule fires for every process on the same sample.
+	m := NewModel(&fakeCollector{})
+	m.alerts = newAlerter([]*alertRule{mustAlertRule(t, alertRuleConfig{Match: "cpu>5"})})
+	procs := make([]ProcessRow, 20)
+	for i := range procs {
+		procs[i] = ProcessRow{PID: int32(100 + i), Name: "busy", CPU: 50}
+	}
+	batch, _ := m.checkProcAlerts(procs)().(tea.BatchMsg)
+	if len(batch) != len(procs) {
+		t.Fatalf("%d hook commands, want one per transition (%d)", len(batch), len(procs))
+	}
+	// Bubble Tea runs each command of a batch in its own goroutine.
+	var wg sync.WaitGroup
+	for _, cmd := range batch {
+		wg.Add(1)
+		go func(cmd tea.Cmd) {
+			defer wg.Done()
+			if msg := cmd().(alertHookMsg); msg.Err != nil {
+				t.Error(msg.Err)
+			}
+		}(cmd)
+	}
+	wg.Wait()
+
+	out, err := os.ReadFile(filepath.Join(dir, "counts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := strings.Fields(string(out))
+	if len(counts) != len(procs) {
+		t.Errorf("%d hooks ran, want %d", len(counts), len(procs))
+	}
+	for _, c := range counts {
+		if n, _ := strconv.Atoi(c); n > alertHookMax {
+			t.Fatalf("%d hooks ran at once, want at most %d", n, alertHookMax)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// batchOptions configures headless --batch output.
+type batchOptions struct {
+	Format     string // "json" (NDJSON), "csv" or "text"
+	Iterations int    // 0 = run until killed
+	Interval   time.Duration
+	Limit      int // 0 = all rows
+	Sort       string
+	Reverse    bool
+	Filter     string
+}
+
+// batchSnapshot is one NDJSON record.
+type batchSnapshot struct {
+	Time     time.Time    `json:"time"`
+	Hostname string       `json:"hostname"`
+	Uptime   string       `json:"uptime"`
+	MemUsed  float64      `json:"mem_used_gb"`
+	MemTotal float64      `json:"mem_total_gb"`
+	Procs    []ProcessRow `json:"procs"`
+}
+
+// runBatch writes process snapshots to stdout until the iteration count is
+// reached, like `top -b`.
+func runBatch(opts batchOptions) error {
+	col, ok := columnByKey(strings.ToLower(opts.Sort))
+	if !ok {
+		return fmt.Errorf("unknown sort column %q (want one of %s)", opts.Sort, columnKeys())
+	}
+
+	var write func(w *bufio.Writer, snap batchSnapshot, first bool) error
+	switch opts.Format {
+	case "json":
+		write = writeBatchJSON
+	case "csv":
+		write = writeBatchCSV
+	case "text":
+		write = writeBatchText
+	default:
+		return fmt.Errorf("unknown batch format %q (want json, csv or text)", opts.Format)
+	}
+
+	m := NewModel(liveCollector{})
+	m.sortCol = col
+	m.sortAsc = col.defaultAsc() != opts.Reverse
+	if err := m.setFilter(opts.Filter); err != nil {
+		return fmt.Errorf("bad filter: %v", err)
+	}
+
+	// Seed the CPU baselines so the first emitted snapshot has real values.
+	m.collector.SysStats()
+	m.collector.Processes(m.neededFields())
+
+	w := bufio.NewWriter(os.Stdout)
+	for i := 0; opts.Iterations == 0 || i < opts.Iterations; i++ {
+		time.Sleep(opts.Interval)
+
+		procs := m.collector.Processes(m.neededFields())
+		if procs.Err != nil {
+			return procs.Err
+		}
+		stats := m.collector.SysStats()
+		if stats.Err != nil {
+			return stats.Err
+		}
+
+		m.allProcs = procs.Procs
+		m.applyFilterAndSort()
+		rows := m.visibleProc
+		if opts.Limit > 0 && len(rows) > opts.Limit {
+			rows = rows[:opts.Limit]
+		}
+
+		snap := batchSnapshot{
+			Time:     time.Now(),
+			Hostname: stats.Hostname,
+			Uptime:   stats.Uptime,
+			MemUsed:  stats.MemUsed,
+			MemTotal: stats.MemTotal,
+			Procs:    rows,
+		}
+		// Flush every snapshot so consumers like jq see it immediately.
+		if err := write(w, snap, i == 0); err != nil {
+			return err
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeBatchJSON(w *bufio.Writer, snap batchSnapshot, _ bool) error {
+	if snap.Procs == nil {
+		snap.Procs = []ProcessRow{}
+	}
+	return json.NewEncoder(w).Encode(snap)
+}
+
+func writeBatchCSV(w *bufio.Writer, snap batchSnapshot, first bool) error {
+	cw := csv.NewWriter(w)
+	if first {
+		cw.Write([]string{"time", "pid", "ppid", "name", "cpu", "mem_mb", "threads", "user"})
+	}
+	ts := snap.Time.Format(time.RFC3339)
+	for _, p := range snap.Procs {
+		cw.Write([]string{
+			ts,
+			strconv.Itoa(int(p.PID)),
+			strconv.Itoa(int(p.PPID)),
+			p.Name,
+			strconv.FormatFloat(p.CPU, 'f', 2, 64),
+			strconv.FormatFloat(p.MemMB, 'f', 1, 64),
+			strconv.Itoa(int(p.Threads)),
+			p.User,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeBatchText(w *bufio.Writer, snap batchSnapshot, first bool) error {
+	if !first {
+		w.WriteString("\n")
+	}
+	fmt.Fprintf(w, "%s  host: %s  uptime: %s  RAM: %.1f / %.1f GB  procs: %d\n",
+		snap.Time.Format("15:04:05"), snap.Hostname, snap.Uptime,
+		snap.MemUsed, snap.MemTotal, len(snap.Procs))
+
+	return writeProcTable(w, snap.Procs)
+}
+
+// writeProcTable prints rows as plain aligned columns, sized to the longest
+// name.
+func writeProcTable(w io.Writer, procs []ProcessRow) error {
+	nameW := len("NAME")
+	for _, p := range procs {
+		if n := len([]rune(p.Name)); n > nameW {
+			nameW = n
+		}
+	}
+	if nameW > colNameMax {
+		nameW = colNameMax
+	}
+
+	fmt.Fprintf(w, "%*s %*s %s %*s %*s %*s %s\n",
+		colPID, "PID", colPID, "PPID", padRight("NAME", nameW),
+		colCPU, "CPU%", colMem, "MEM(MB)", colStatus, "THRD", "USER")
+	for _, p := range procs {
+		_, err := fmt.Fprintf(w, "%*d %*d %s %*.2f %*.1f %*d %s\n",
+			colPID, p.PID, colPID, p.PPID, padRight(truncate(p.Name, nameW), nameW),
+			colCPU, p.CPU, colMem, p.MemMB, colStatus, p.Threads, p.User)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
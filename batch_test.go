@@ -0,0 +1,31 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBatchJSONOmitsUnsetStart(t *testing.T) {
+	started := time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	err := writeBatchJSON(w, batchSnapshot{Time: started, Procs: []ProcessRow{
+		{PID: 1, Name: "init"},
+		{PID: 2, Name: "kthreadd", Started: started},
+	}}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Flush()
+
+	out := buf.String()
+	if strings.Contains(out, "0001-01-01") {
+		t.Errorf("unset start time encoded as the zero time:\n%s", out)
+	}
+	if n := strings.Count(out, `"started":`); n != 1 {
+		t.Errorf(`%d "started" keys, want 1 (only the row that has one):`+"\n%s", n, out)
+	}
+}
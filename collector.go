@@ -0,0 +1,15 @@
+package main
+
+// Collector supplies the samples the model displays. NewModel takes one so
+// the TUI can be driven by something other than the live system: a scripted
+// fake in tests, or a recording.
+type Collector interface {
+	SysStats() sysStatsMsg
+	Processes(fields procField) processesMsg
+}
+
+// liveCollector reads the running system through gopsutil.
+type liveCollector struct{}
+
+func (liveCollector) SysStats() sysStatsMsg                   { return CollectSysStats() }
+func (liveCollector) Processes(fields procField) processesMsg { return CollectProcesses(fields) }
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fakeCollector replays scripted samples. Each call returns the next frame;
+// once the script runs out the last frame repeats. The fields requested for
+// each process sample are recorded.
+type fakeCollector struct {
+	sys    []sysStatsMsg
+	procs  [][]ProcessRow
+	fields []procField
+
+	sysCalls, procCalls int
+}
+
+func (f *fakeCollector) SysStats() sysStatsMsg {
+	if len(f.sys) == 0 {
+		return sysStatsMsg{}
+	}
+	i := min(f.sysCalls, len(f.sys)-1)
+	f.sysCalls++
+	return f.sys[i]
+}
+
+func (f *fakeCollector) Processes(fields procField) processesMsg {
+	f.fields = append(f.fields, fields)
+	if len(f.procs) == 0 {
+		return processesMsg{}
+	}
+	i := min(f.procCalls, len(f.procs)-1)
+	f.procCalls++
+	// Copy so the model can't alias the script.
+	return processesMsg{Procs: append([]ProcessRow(nil), f.procs[i]...)}
+}
+
+// runCmd executes cmd and feeds every message it produces back into m,
+// expanding batches. Ticks (and anything else that doesn't answer at once,
+// like the cursor blink) are dropped so the loop terminates.
+func runCmd(m Model, cmd tea.Cmd) Model {
+	if cmd == nil {
+		return m
+	}
+	done := make(chan tea.Msg, 1)
+	go func() { done <- cmd() }()
+	var msg tea.Msg
+	select {
+	case msg = <-done:
+	case <-time.After(50 * time.Millisecond):
+		return m
+	}
+	switch msg := msg.(type) {
+	case tea.BatchMsg:
+		for _, c := range msg {
+			m = runCmd(m, c)
+		}
+	case tickMsg, nil:
+	default:
+		next, cmd := m.Update(msg)
+		m = runCmd(next.(Model), cmd)
+	}
+	return m
+}
+
+// press sends one key to m and runs whatever it returns.
+func press(m Model, key string) Model {
+	var msg tea.KeyMsg
+	switch key {
+	case "esc":
+		msg = tea.KeyMsg{Type: tea.KeyEsc}
+	case "enter":
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+	case " ":
+		msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	default:
+		msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	}
+	next, cmd := m.Update(msg)
+	return runCmd(next.(Model), cmd)
+}
+
+func TestFakeCollectorDrivesUpdate(t *testing.T) {
+	fc := &fakeCollector{procs: [][]ProcessRow{
+		{{PID: 1, Name: "init", CPU: 0}},
+		{{PID: 1, Name: "init", CPU: 1}, {PID: 2, Name: "worker", CPU: 90}},
+	}}
+	m := NewModel(fc)
+	m = runCmd(m, m.Init())
+	if len(m.visibleProc) != 1 {
+		t.Fatalf("after Init: %d rows, want 1", len(m.visibleProc))
+	}
+
+	// A tick samples again; the second frame sorts the busy worker first.
+	next, cmd := m.Update(tickMsg{gen: m.tickGen})
+	m = runCmd(next.(Model), cmd)
+	if len(m.visibleProc) != 2 || m.visibleProc[0].PID != 2 {
+		t.Fatalf("after tick: rows %v, want worker first", m.visibleProc)
+	}
+
+	// While paused ticks are ignored and the snapshot stays put.
+	m = press(m, keyPause)
+	calls := fc.procCalls
+	next, cmd = m.Update(tickMsg{gen: m.tickGen})
+	runCmd(next.(Model), cmd)
+	if fc.procCalls != calls {
+		t.Errorf("paused tick sampled processes (%d calls, want %d)", fc.procCalls, calls)
+	}
+
+	// The collector is asked for the fields the view needs.
+	m = press(m, keyPause)
+	m.setFilter("cmd:foo")
+	runCmd(m, m.fetchProcesses())
+	if got := fc.fields[len(fc.fields)-1]; got&fieldCmdline == 0 {
+		t.Errorf("fields %b: cmd: filter should request the command line", got)
+	}
+}
+
+func TestStepIgnoresStaleReply(t *testing.T) {
+	fc := &fakeCollector{procs: [][]ProcessRow{
+		{{PID: 1, Name: "init"}},
+		{{PID: 1, Name: "init"}, {PID: 2, Name: "stale"}},
+		{{PID: 1, Name: "init"}, {PID: 2, Name: "stale"}, {PID: 3, Name: "step"}},
+	}}
+	m := NewModel(fc)
+	m = runCmd(m, m.Init())
+
+	// A fetch from before the pause is still in flight when the user steps,
+	// and answers first.
+	inFlight := m.fetchProcesses()
+	m = press(m, keyPause)
+	next, stepCmd := m.Update(keyMsg(keyStep))
+	m = send(next.(Model), inFlight())
+	if len(m.visibleProc) != 1 {
+		t.Fatalf("stale reply applied while paused: %d rows, want 1", len(m.visibleProc))
+	}
+	m = runCmd(m, stepCmd)
+	if len(m.visibleProc) != 3 {
+		t.Errorf("after the step's own reply: %d rows, want 3", len(m.visibleProc))
+	}
+}
+
+func TestFilterFetchesNewFields(t *testing.T) {
+	// The first sample was taken without ports, as it would be live.
+	fc := &fakeCollector{procs: [][]ProcessRow{
+		{{PID: 1, Name: "init"}, {PID: 2, Name: "nginx"}},
+		{{PID: 1, Name: "init"}, {PID: 2, Name: "nginx", Ports: []uint32{8080}}},
+	}}
+	m := NewModel(fc)
+	m = runCmd(m, m.Init())
+
+	// Even while paused, typing port: samples again with the ports.
+	m = press(m, keyPause)
+	m = press(m, keyFilter)
+	for _, r := range "port:8080" {
+		m = press(m, string(r))
+	}
+	if got := fc.fields[len(fc.fields)-1]; got&fieldPorts == 0 {
+		t.Errorf("fields %b: port: filter should request the ports", got)
+	}
+	if len(m.visibleProc) != 1 || m.visibleProc[0].PID != 2 {
+		t.Errorf("rows %v, want nginx", m.visibleProc)
+	}
+}
@@ -0,0 +1,566 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// column describes one process-table column: how wide it is, how it is
+// aligned, how a row is formatted into it and how two rows compare on it.
+// Every column is sortable; the SortColumn value doubles as its identity.
+type column struct {
+	id     SortColumn
+	key    string // stable name used by --sort and the config file
+	title  string
+	width  int  // 0 = flexible, shares the space left over
+	right  bool // right-align (numbers)
+	asc    bool // natural sort direction when first selected
+	fields procField
+	help   string
+	format func(m *Model, idx int, r ProcessRow) string
+	less   func(m *Model, a, b ProcessRow) bool
+	warn   func(r ProcessRow) bool // optional: draw the cell in the warning colour
+}
+
+// columnRegistry lists every available column in chooser order.
+var columnRegistry = []column{
+	{
+		id: SortPID, key: "pid", title: "PID", width: colPID, right: true, asc: true,
+		help:   "Process ID assigned by the operating system",
+		format: func(_ *Model, _ int, r ProcessRow) string { return fmt.Sprint(r.PID) },
+		less:   func(_ *Model, a, b ProcessRow) bool { return a.PID < b.PID },
+	},
+	{
+		id: SortPPID, key: "ppid", title: "PPID", width: colPID, right: true, asc: true,
+		help:   "Parent process ID",
+		format: func(_ *Model, _ int, r ProcessRow) string { return fmt.Sprint(r.PPID) },
+		less:   func(_ *Model, a, b ProcessRow) bool { return a.PPID < b.PPID },
+	},
+	{
+		id: SortName, key: "name", title: "NAME", asc: true,
+		help:   "Executable name (truncated with … if longer than column)",
+		format: formatNameCell,
+		less: func(_ *Model, a, b ProcessRow) bool {
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		},
+	},
+	{
+		id: SortCmdline, key: "cmdline", title: "COMMAND", asc: true, fields: fieldCmdline,
+		help: "Full command line",
+		format: func(_ *Model, _ int, r ProcessRow) string {
+			if r.Cmdline == "" {
+				return "[" + r.Name + "]" // kernel threads have no argv
+			}
+			return r.Cmdline
+		},
+		less: func(_ *Model, a, b ProcessRow) bool { return a.Cmdline < b.Cmdline },
+	},
+	{
+		id: SortState, key: "state", title: "S", width: 3, asc: true, fields: fieldState,
+		help:   "State: R run, S sleep, D disk wait, T stopped, Z zombie, I idle",
+		format: func(_ *Model, _ int, r ProcessRow) string { return r.State },
+		less:   func(_ *Model, a, b ProcessRow) bool { return a.State < b.State },
+	},
+	{
+		id: SortNice, key: "nice", title: "NI", width: 4, right: true, fields: fieldNice,
+		help:   "Nice value (-20 highest priority … 19 lowest)",
+		format: func(_ *Model, _ int, r ProcessRow) string { return fmt.Sprint(r.Nice) },
+		less:   func(_ *Model, a, b ProcessRow) bool { return a.Nice < b.Nice },
+	},
+	{
+		id: SortPriority, key: "priority", title: "PRI", width: 4, right: true, fields: fieldNice,
+		help:   "Scheduling priority (20 + nice for normal tasks)",
+		format: func(_ *Model, _ int, r ProcessRow) string { return fmt.Sprint(r.Priority) },
+		less:   func(_ *Model, a, b ProcessRow) bool { return a.Priority < b.Priority },
+	},
+	{
+		id: SortCPU, key: "cpu", title: "CPU%", width: colCPU, right: true,
+		help:   "CPU usage across all cores — can exceed 100% on multi-core",
+		format: func(_ *Model, _ int, r ProcessRow) string { return fmt.Sprintf("%.2f", r.CPU) },
+		less:   func(_ *Model, a, b ProcessRow) bool { return a.CPU < b.CPU },
+	},
+	{
+		id: SortCPUHist, key: "cpu_hist", title: "CPU HIST", width: colSpark,
+		help:   "CPU% over the last few ticks, newest on the right (sorts by average)",
+		format: func(m *Model, _ int, r ProcessRow) string { return m.sparkCell(r.PID, true) },
+		less: func(m *Model, a, b ProcessRow) bool {
+			return m.cpuHistAvg(a.PID) < m.cpuHistAvg(b.PID)
+		},
+	},
+	{
+		id: SortVSZ, key: "vsz", title: "VSZ(MB)", width: colMem, right: true,
+		help:   "Virtual memory size in megabytes",
+		format: func(_ *Model, _ int, r ProcessRow) string { return fmt.Sprintf("%.1f", r.VSZMB) },
+		less:   func(_ *Model, a, b ProcessRow) bool { return a.VSZMB < b.VSZMB },
+	},
+	{
+		id: SortMem, key: "mem", title: "MEM(MB)", width: colMem, right: true,
+		help:   "Resident set size: physical RAM in use, in megabytes",
+		format: func(_ *Model, _ int, r ProcessRow) string { return fmt.Sprintf("%.1f", r.MemMB) },
+		less:   func(_ *Model, a, b ProcessRow) bool { return a.MemMB < b.MemMB },
+	},
+	{
+		id: SortMemHist, key: "mem_hist", title: "MEM HIST", width: colSpark,
+		help:   "RSS trend over the last few ticks, relative to its peak (sorts by growth)",
+		format: func(m *Model, _ int, r ProcessRow) string { return m.sparkCell(r.PID, false) },
+		less: func(m *Model, a, b ProcessRow) bool {
+			return m.memHistGrowth(a.PID) < m.memHistGrowth(b.PID)
+		},
+	},
+	{
+		id: SortShared, key: "shared", title: "SHR(MB)", width: colMem, right: true, fields: fieldShared,
+		help:   "Shared (file-backed) resident memory in megabytes — Linux only",
+		format: func(_ *Model, _ int, r ProcessRow) string { return fmt.Sprintf("%.1f", r.SharedMB) },
+		less:   func(_ *Model, a, b ProcessRow) bool { return a.SharedMB < b.SharedMB },
+	},
+	{
+		id: SortCPUTime, key: "cpu_time", title: "TIME+", width: 10, right: true, fields: fieldTimes,
+		help:   "Total CPU time consumed (user + system)",
+		format: func(_ *Model, _ int, r ProcessRow) string { return formatCPUTime(r.CPUTime) },
+		less:   func(_ *Model, a, b ProcessRow) bool { return a.CPUTime < b.CPUTime },
+	},
+	{
+		id: SortStart, key: "start", title: "START", width: 6, right: true, asc: true, fields: fieldStart,
+		help:   "Start time (HH:MM today, otherwise month and day)",
+		format: func(_ *Model, _ int, r ProcessRow) string { return formatStart(r.Started) },
+		less:   func(_ *Model, a, b ProcessRow) bool { return a.Started.Before(b.Started) },
+	},
+	{
+		id: SortElapsed, key: "elapsed", title: "ELAPSED", width: 9, right: true, fields: fieldStart,
+		help:   "Time since the process started",
+		format: func(_ *Model, _ int, r ProcessRow) string { return formatElapsed(r.Started) },
+		// Longer-running means an earlier start.
+		less: func(_ *Model, a, b ProcessRow) bool { return a.Started.After(b.Started) },
+	},
+	{
+		id: SortThreads, key: "threads", title: "THRD", width: colStatus, right: true,
+		help:   "Number of OS threads owned by the process",
+		format: func(_ *Model, _ int, r ProcessRow) string { return fmt.Sprint(r.Threads) },
+		less:   func(_ *Model, a, b ProcessRow) bool { return a.Threads < b.Threads },
+	},
+	{
+		id: SortFDs, key: "fds", title: "FDS", width: 6, right: true, fields: fieldFDs,
+		help:   "Open file descriptors (red near the RLIMIT_NOFILE soft limit)",
+		format: func(_ *Model, _ int, r ProcessRow) string { return fmt.Sprint(r.FDs) },
+		less:   func(_ *Model, a, b ProcessRow) bool { return a.FDs < b.FDs },
+		warn:   nearFDLimit,
+	},
+	{
+		id: SortRead, key: "read", title: "READ/s", width: 9, right: true, fields: fieldIO,
+		help:   "Disk bytes read per second",
+		format: func(_ *Model, _ int, r ProcessRow) string { return formatRate(r.ReadBps) },
+		less:   func(_ *Model, a, b ProcessRow) bool { return a.ReadBps < b.ReadBps },
+	},
+	{
+		id: SortWrite, key: "write", title: "WRITE/s", width: 9, right: true, fields: fieldIO,
+		help:   "Disk bytes written per second",
+		format: func(_ *Model, _ int, r ProcessRow) string { return formatRate(r.WriteBps) },
+		less:   func(_ *Model, a, b ProcessRow) bool { return a.WriteBps < b.WriteBps },
+	},
+	{
+		id: SortUser, key: "user", title: "USER", width: colUser, asc: true,
+		help:   "Account that owns the process (N/A if access is denied)",
+		format: func(_ *Model, _ int, r ProcessRow) string { return r.User },
+		less: func(_ *Model, a, b ProcessRow) bool {
+			return strings.ToLower(a.User) < strings.ToLower(b.User)
+		},
+	},
+	{
+		id: SortGroup, key: "group", title: "GROUP", width: 10, asc: true, fields: fieldGroup,
+		help:   "Effective group of the process",
+		format: func(_ *Model, _ int, r ProcessRow) string { return r.Group },
+		less:   func(_ *Model, a, b ProcessRow) bool { return a.Group < b.Group },
+	},
+	{
+		id: SortTTY, key: "tty", title: "TTY", width: 7, asc: true, fields: fieldTTY,
+		help:   "Controlling terminal (blank if none)",
+		format: func(_ *Model, _ int, r ProcessRow) string { return r.TTY },
+		less:   func(_ *Model, a, b ProcessRow) bool { return a.TTY < b.TTY },
+	},
+}
+
+// defaultColumns is the column set shown on first start.
+var defaultColumns = []SortColumn{
+	SortPID, SortName, SortCPU, SortCPUHist, SortMem, SortMemHist, SortThreads, SortUser,
+}
+
+var columnIndex = func() map[SortColumn]int {
+	idx := make(map[SortColumn]int, len(columnRegistry))
+	for i, c := range columnRegistry {
+		idx[c.id] = i
+	}
+	return idx
+}()
+
+// columnDef returns the registry entry for id.
+func columnDef(id SortColumn) *column {
+	return &columnRegistry[columnIndex[id]]
+}
+
+// columnByKey looks a column up by its key ("cpu", "rss", …).
+func columnByKey(key string) (SortColumn, bool) {
+	for _, c := range columnRegistry {
+		if c.key == key {
+			return c.id, true
+		}
+	}
+	return 0, false
+}
+
+// columnKeys lists every column key, for error messages.
+func columnKeys() string {
+	keys := make([]string, len(columnRegistry))
+	for i, c := range columnRegistry {
+		keys[i] = c.key
+	}
+	return strings.Join(keys, ", ")
+}
+
+// ---------------------------------------------------------------------------
+// Visible columns & layout
+// ---------------------------------------------------------------------------
+
+// visibleColumns returns the shown columns in display order.
+func (m *Model) visibleColumns() []SortColumn {
+	out := make([]SortColumn, 0, len(m.colOrder))
+	for _, id := range m.colOrder {
+		if m.colShown[id] {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// neededFields is the union of optional fields required by the visible
+// columns, the sort key, the filter and the alert rules, passed to
+// CollectProcesses each tick. The state is always read: it drives the
+// header counts and row colours. So is the start time, which tells a
+// recycled PID from the process whose history it inherited; the handle
+// caches it, so it is read once per process rather than every tick.
+func (m *Model) neededFields() procField {
+	f := columnDef(m.sortCol).fields | fieldState | fieldStart
+	if m.filterQuery != nil {
+		f |= m.filterQuery.fields
+	}
+	if m.alerts != nil {
+		f |= m.alerts.fields
+	}
+	for _, id := range m.visibleColumns() {
+		f |= columnDef(id).fields
+	}
+	return f
+}
+
+// layoutColumns returns the visible columns that fit the terminal, with
+// their widths. Columns that would overflow are dropped from the right.
+func (m *Model) layoutColumns() ([]SortColumn, []int) {
+	cols := m.visibleColumns()
+	for {
+		widths := m.columnWidths(cols)
+		total := 2 + 3*(len(cols)-1)
+		for _, w := range widths {
+			total += w
+		}
+		if total <= m.termWidth || len(cols) == 1 {
+			return cols, widths
+		}
+		cols = cols[:len(cols)-1]
+	}
+}
+
+// columnWidths computes the width of each visible column. Fixed columns
+// keep their declared width; flexible ones (NAME, COMMAND) split what's
+// left, with NAME capped at colNameMax when COMMAND can take the rest.
+func (m *Model) columnWidths(cols []SortColumn) []int {
+	widths := make([]int, len(cols))
+	used := 2 + 3*(len(cols)-1) // left margin + " │ " separators
+	var flex []int
+	for i, id := range cols {
+		if w := columnDef(id).width; w > 0 {
+			widths[i] = w
+			used += w
+		} else {
+			flex = append(flex, i)
+		}
+	}
+	if len(flex) == 0 {
+		return widths
+	}
+
+	// NAME and COMMAND are the only flexible columns. Alone, either takes
+	// all the free space (NAME up to colNameMax); together NAME gets a third.
+	free := m.termWidth - used
+	nameW, cmdW := free, free
+	if len(flex) == 2 {
+		nameW = free / 3
+	}
+	if nameW > colNameMax {
+		nameW = colNameMax
+	}
+	if nameW < colNameMin {
+		nameW = colNameMin
+	}
+	if len(flex) == 2 {
+		cmdW = free - nameW
+	}
+	if cmdW < colNameMin {
+		cmdW = colNameMin
+	}
+	for _, i := range flex {
+		if cols[i] == SortName {
+			widths[i] = nameW
+		} else {
+			widths[i] = cmdW
+		}
+	}
+	return widths
+}
+
+// ---------------------------------------------------------------------------
+// Cell formatting
+// ---------------------------------------------------------------------------
+
+func formatNameCell(m *Model, idx int, r ProcessRow) string {
+	label := r.Name
+	if m.treeMode && idx < len(m.treeLines) {
+		tl := m.treeLines[idx]
+		label = tl.prefix + r.Name
+		if tl.folded {
+			label += fmt.Sprintf(" [+%d]", tl.hidden)
+		}
+	}
+	return label
+}
+
+func (m *Model) sparkCell(pid int32, cpu bool) string {
+	h, ok := m.procHist[pid]
+	if !ok {
+		return ""
+	}
+	if cpu {
+		return sparkline(h.cpu.last(colSpark), colSpark, 100)
+	}
+	return sparkline(h.mem.last(colSpark), colSpark, 0)
+}
+
+func (m *Model) cpuHistAvg(pid int32) float64 {
+	h, ok := m.procHist[pid]
+	if !ok {
+		return 0
+	}
+	s := h.cpu.last(historyLen)
+	if len(s) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range s {
+		sum += v
+	}
+	return sum / float64(len(s))
+}
+
+func (m *Model) memHistGrowth(pid int32) float64 {
+	h, ok := m.procHist[pid]
+	if !ok {
+		return 0
+	}
+	s := h.mem.last(historyLen)
+	if len(s) < 2 {
+		return 0
+	}
+	return s[len(s)-1] - s[0]
+}
+
+// formatCPUTime renders seconds like top's TIME+: M:SS.hh, or H:MM:SS
+// once past an hour.
+func formatCPUTime(sec float64) string {
+	if sec >= 3600 {
+		s := int(sec)
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	m := int(sec) / 60
+	return fmt.Sprintf("%d:%05.2f", m, sec-float64(m*60))
+}
+
+func formatStart(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	now := time.Now()
+	if t.YearDay() == now.YearDay() && t.Year() == now.Year() {
+		return t.Format("15:04")
+	}
+	return t.Format("Jan02")
+}
+
+func formatElapsed(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	d := time.Since(t)
+	days := int(d.Hours()) / 24
+	if days > 0 {
+		return fmt.Sprintf("%dd%02dh", days, int(d.Hours())%24)
+	}
+	s := int(d.Seconds())
+	return fmt.Sprintf("%02d:%02d:%02d", s/3600, s/60%60, s%60)
+}
+
+// formatRate renders bytes/second with a binary unit suffix.
+func formatRate(bps float64) string {
+	switch {
+	case bps >= 1<<30:
+		return fmt.Sprintf("%.1fG", bps/(1<<30))
+	case bps >= 1<<20:
+		return fmt.Sprintf("%.1fM", bps/(1<<20))
+	case bps >= 1<<10:
+		return fmt.Sprintf("%.1fK", bps/(1<<10))
+	default:
+		return fmt.Sprintf("%.0f", bps)
+	}
+}
+
+// defaultColumnLayout returns every column with defaultColumns first (and
+// shown), followed by the rest in registry order (hidden).
+func defaultColumnLayout() ([]SortColumn, map[SortColumn]bool) {
+	return columnLayout(defaultColumns)
+}
+
+// columnLayout puts visible first, in the given order, then every other
+// registry column hidden.
+func columnLayout(visible []SortColumn) ([]SortColumn, map[SortColumn]bool) {
+	shown := make(map[SortColumn]bool, len(columnRegistry))
+	order := make([]SortColumn, 0, len(columnRegistry))
+	for _, id := range visible {
+		if !shown[id] {
+			shown[id] = true
+			order = append(order, id)
+		}
+	}
+	for _, c := range columnRegistry {
+		if !shown[c.id] {
+			order = append(order, c.id)
+		}
+	}
+	return order, shown
+}
+
+// ---------------------------------------------------------------------------
+// Column chooser
+// ---------------------------------------------------------------------------
+
+func (m Model) handleColumnsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.statusMsg = ""
+
+	switch msg.String() {
+	case keyEsc, keyEnter, keyColumns, keyQuit:
+		m.mode = ModeNormal
+		// Newly shown columns may need fields we haven't collected yet.
+		return m, m.fetchProcesses()
+
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case keyUp, keyVimUp:
+		if m.colCursor > 0 {
+			m.colCursor--
+		}
+
+	case keyDown, keyVimDown:
+		if m.colCursor < len(m.colOrder)-1 {
+			m.colCursor++
+		}
+
+	case keyMark:
+		id := m.colOrder[m.colCursor]
+		if m.colShown[id] && len(m.visibleColumns()) == 1 {
+			m.statusMsg = "at least one column must stay visible"
+			break
+		}
+		m.colShown[id] = !m.colShown[id]
+
+	case keyMoveUp:
+		if i := m.colCursor; i > 0 {
+			m.colOrder[i-1], m.colOrder[i] = m.colOrder[i], m.colOrder[i-1]
+			m.colCursor--
+		}
+
+	case keyMoveDown:
+		if i := m.colCursor; i < len(m.colOrder)-1 {
+			m.colOrder[i+1], m.colOrder[i] = m.colOrder[i], m.colOrder[i+1]
+			m.colCursor++
+		}
+
+	case keySortBy:
+		m.toggleSort(m.colOrder[m.colCursor])
+	}
+	return m, nil
+}
+
+func (m *Model) renderColumnsScreen() string {
+	var b strings.Builder
+	indent := "  "
+
+	b.WriteString(m.renderHeader())
+	b.WriteString("\n")
+	b.WriteString(m.renderSeparator())
+	b.WriteString("\n")
+	b.WriteString(styleHelpTitle.Render(indent + "Columns"))
+	b.WriteString("\n\n")
+
+	// Scroll the list so the cursor stays on screen.
+	bodyH := m.termHeight - m.headerHeight() - 6
+	if bodyH < 1 {
+		bodyH = 1
+	}
+	start := 0
+	if m.colCursor >= bodyH {
+		start = m.colCursor - bodyH + 1
+	}
+	end := start + bodyH
+	if end > len(m.colOrder) {
+		end = len(m.colOrder)
+	}
+
+	for i := start; i < end; i++ {
+		id := m.colOrder[i]
+		c := columnDef(id)
+		check := "[ ]"
+		if m.colShown[id] {
+			check = "[x]"
+		}
+		sortMark := "  "
+		if m.sortCol == id {
+			sortMark = " ▼"
+			if m.sortAsc {
+				sortMark = " ▲"
+			}
+		}
+		line := fmt.Sprintf("%s %-9s%s  %s", check, c.title, sortMark, c.help)
+		if i == m.colCursor {
+			b.WriteString(styleCursor.Render("▶ ") + styleOverlaySelected.Render(line))
+		} else if m.colShown[id] {
+			b.WriteString("  " + styleHelpDesc.Render(line))
+		} else {
+			b.WriteString("  " + styleStatusBar.Render(line))
+		}
+		b.WriteString("\n")
+	}
+	for i := end - start; i < bodyH; i++ {
+		b.WriteString("\n")
+	}
+
+	b.WriteString(m.renderSeparator())
+	b.WriteString("\n")
+	if m.statusMsg != "" {
+		b.WriteString(styleStatusError.Render(indent + m.statusMsg))
+	} else {
+		b.WriteString(styleStatusBar.Render(indent + fmt.Sprintf("%s show/hide  ·  %s/%s move down/up  ·  %s sort by  ·  %s done",
+			keyLabel(keyMark), keyLabel(keyMoveDown), keyLabel(keyMoveUp), keyLabel(keySortBy),
+			keysLabel(keyEnter, keyEsc, keyColumns))))
+	}
+	return b.String()
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Refresh interval bounds accepted from the config file.
+const (
+	minTickInterval = 250 * time.Millisecond
+	maxTickInterval = 10 * time.Second
+)
+
+// fileConfig mirrors the TOML config file. Every setting is optional;
+// anything left out keeps its built-in default.
+//
+//	refresh     = "2s"
+//	sort        = "mem"
+//	reverse     = false
+//	filter      = "user:postgres"
+//	filter_mode = "fuzzy"
+//	columns     = ["pid", "name", "cpu", "mem", "user"]
+//
+//	[thresholds]
+//	high_cpu   = 80
+//	avail_warn = 15
+//	swap_warn  = 25
+//	fd_warn    = 80
+//
+//	[colors]
+//	accent = "#5fafff"
+//
+//	[keys]
+//	tree = "T"
+//
+//	[alerts]
+//	exec = "notify-send gomon \"$GOMON_ALERT_MESSAGE\""
+//	log  = "/var/log/gomon-alerts.log"
+//
+//	[[alerts.rule]]
+//	name  = "runaway java"
+//	match = "name:java cpu>90"
+//	for   = "30s"
+//
+//	[[alerts.rule]]
+//	name   = "low memory"
+//	system = "avail<5"
+type fileConfig struct {
+	Refresh    time.Duration `toml:"refresh"`
+	Sort       string        `toml:"sort"`
+	Reverse    bool          `toml:"reverse"`
+	Filter     string        `toml:"filter"`
+	FilterMode string        `toml:"filter_mode"`
+	Columns    []string      `toml:"columns"`
+	Thresholds struct {
+		HighCPU   float64 `toml:"high_cpu"`
+		AvailWarn float64 `toml:"avail_warn"`
+		SwapWarn  float64 `toml:"swap_warn"`
+		FDWarn    float64 `toml:"fd_warn"`
+	} `toml:"thresholds"`
+	Colors map[string]string `toml:"colors"`
+	Keys   map[string]string `toml:"keys"`
+	Alerts struct {
+		Exec  string            `toml:"exec"`
+		Log   string            `toml:"log"`
+		Rules []alertRuleConfig `toml:"rule"`
+	} `toml:"alerts"`
+}
+
+// themeColors maps [colors] names to the palette in styles.go.
+var themeColors = map[string]*lipgloss.Color{
+	"accent":     &colorAccent,
+	"selected":   &colorSelected,
+	"high_cpu":   &colorHighCPU,
+	"muted":      &colorMuted,
+	"green":      &colorGreen,
+	"text":       &colorWhite,
+	"background": &colorBg,
+	"marked":     &colorMarked,
+	"zombie":     &colorZombie,
+	"blocked":    &colorBlocked,
+}
+
+// defaultConfigPath is $XDG_CONFIG_HOME/gomon/config, falling back to
+// ~/.config when XDG_CONFIG_HOME is unset.
+func defaultConfigPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "gomon", "config")
+}
+
+// loadConfig reads and applies the config file at path, or at the default
+// location when path is empty. A missing default file is not an error.
+// Settings whose command-line flag was given explicitly (flagSet) are left
+// to the flag.
+func loadConfig(path string, flagSet map[string]bool) error {
+	explicit := path != ""
+	if !explicit {
+		path = defaultConfigPath()
+		if path == "" {
+			return nil
+		}
+	}
+
+	var cfg fileConfig
+	md, err := toml.DecodeFile(path, &cfg)
+	if errors.Is(err, fs.ErrNotExist) {
+		if !explicit {
+			return nil
+		}
+		return fmt.Errorf("config: %v", err)
+	}
+	if err != nil {
+		return fmt.Errorf("config %s: %v", path, err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return fmt.Errorf("config %s: unknown setting %q", path, undecoded[0].String())
+	}
+	if err := applyConfig(&cfg, md, flagSet); err != nil {
+		return fmt.Errorf("config %s: %v", path, err)
+	}
+	return nil
+}
+
+// applyConfig validates cfg and copies it into the package defaults.
+func applyConfig(cfg *fileConfig, md toml.MetaData, flagSet map[string]bool) error {
+	if md.IsDefined("refresh") {
+		if cfg.Refresh < minTickInterval || cfg.Refresh > maxTickInterval {
+			return fmt.Errorf("refresh %v is outside %v–%v", cfg.Refresh, minTickInterval, maxTickInterval)
+		}
+		tickInterval = cfg.Refresh
+	}
+
+	if cfg.Sort != "" {
+		col, ok := columnByKey(cfg.Sort)
+		if !ok {
+			return fmt.Errorf("sort: unknown column %q (want one of %s)", cfg.Sort, columnKeys())
+		}
+		defaultSort = col
+	}
+	defaultSortAsc = defaultSort.defaultAsc() != cfg.Reverse
+
+	switch cfg.FilterMode {
+	case "", "substr", "substring":
+	case "regex":
+		defaultFilterMode = matchRegex
+	case "fuzzy":
+		defaultFilterMode = matchFuzzy
+	default:
+		return fmt.Errorf("filter_mode: unknown mode %q (want substr, regex or fuzzy)", cfg.FilterMode)
+	}
+	if cfg.Filter != "" {
+		if _, err := parseQuery(cfg.Filter, matchOptions{mode: defaultFilterMode}); err != nil {
+			return fmt.Errorf("filter: %v", err)
+		}
+		defaultFilter = cfg.Filter
+	}
+
+	if md.IsDefined("columns") {
+		if len(cfg.Columns) == 0 {
+			return fmt.Errorf("columns: at least one column is required")
+		}
+		cols := make([]SortColumn, 0, len(cfg.Columns))
+		for _, key := range cfg.Columns {
+			col, ok := columnByKey(key)
+			if !ok {
+				return fmt.Errorf("columns: unknown column %q (want one of %s)", key, columnKeys())
+			}
+			cols = append(cols, col)
+		}
+		defaultColumns = cols
+	}
+
+	thresholds := []struct {
+		key, flag string
+		val       float64
+		dst       *float64
+	}{
+		{"high_cpu", "", cfg.Thresholds.HighCPU, &highCPUThresh},
+		{"avail_warn", "avail-warn", cfg.Thresholds.AvailWarn, &availWarnPct},
+		{"swap_warn", "swap-warn", cfg.Thresholds.SwapWarn, &swapWarnPct},
+		{"fd_warn", "", cfg.Thresholds.FDWarn, &fdWarnPct},
+	}
+	for _, t := range thresholds {
+		if !md.IsDefined("thresholds", t.key) {
+			continue
+		}
+		if t.val < 0 || (t.key != "high_cpu" && t.val > 100) {
+			return fmt.Errorf("thresholds.%s: %g is out of range", t.key, t.val)
+		}
+		if !flagSet[t.flag] {
+			*t.dst = t.val
+		}
+	}
+
+	if len(cfg.Colors) > 0 {
+		for _, name := range sortedKeys(cfg.Colors) {
+			dst, ok := themeColors[name]
+			if !ok {
+				return fmt.Errorf("colors: unknown colour %q (want one of %s)",
+					name, strings.Join(sortedKeys(themeColors), ", "))
+			}
+			val := cfg.Colors[name]
+			if !validColor(val) {
+				return fmt.Errorf("colors.%s: %q is not an ANSI colour (0–255) or #rgb/#rrggbb", name, val)
+			}
+			*dst = lipgloss.Color(val)
+		}
+		buildStyles()
+	}
+
+	if len(cfg.Keys) > 0 {
+		if err := remapKeys(cfg.Keys); err != nil {
+			return err
+		}
+	}
+
+	rules := make([]*alertRule, 0, len(cfg.Alerts.Rules))
+	for i, rc := range cfg.Alerts.Rules {
+		r, err := newAlertRule(rc)
+		if err != nil {
+			return fmt.Errorf("alerts.rule[%d]: %v", i, err)
+		}
+		rules = append(rules, r)
+	}
+	alertRules, alertExec, alertLog = rules, cfg.Alerts.Exec, cfg.Alerts.Log
+	return nil
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+func validColor(s string) bool {
+	if hexColor.MatchString(s) {
+		return true
+	}
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 0 && n <= 255
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
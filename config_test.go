@@ -0,0 +1,150 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// saveConfig returns a function restoring every setting the config file can
+// change, for tests that load one.
+func saveConfig() func() {
+	restoreKeys := saveKeys()
+	tick, cpu, sort, asc := tickInterval, highCPUThresh, defaultSort, defaultSortAsc
+	filter, mode, cols := defaultFilter, defaultFilterMode, defaultColumns
+	avail, swap, fd := availWarnPct, swapWarnPct, fdWarnPct
+	rules, exec, log := alertRules, alertExec, alertLog
+	colors := map[string]lipgloss.Color{}
+	for name, c := range themeColors {
+		colors[name] = *c
+	}
+	return func() {
+		restoreKeys()
+		tickInterval, highCPUThresh, defaultSort, defaultSortAsc = tick, cpu, sort, asc
+		defaultFilter, defaultFilterMode, defaultColumns = filter, mode, cols
+		availWarnPct, swapWarnPct, fdWarnPct = avail, swap, fd
+		alertRules, alertExec, alertLog = rules, exec, log
+		for name, c := range colors {
+			*themeColors[name] = c
+		}
+		buildStyles()
+	}
+}
+
+func writeConfig(t *testing.T, text string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	defer saveConfig()()
+	path := writeConfig(t, `
+refresh     = "2s"
+sort        = "mem"
+reverse     = true
+filter      = "user:postgres"
+filter_mode = "fuzzy"
+columns     = ["pid", "name", "mem"]
+
+[thresholds]
+high_cpu   = 80
+avail_warn = 15
+swap_warn  = 25
+
+[colors]
+accent = "#5fafff"
+
+[keys]
+tree = "T"
+mark = "space"
+
+[alerts]
+exec = "true"
+
+[[alerts.rule]]
+name  = "runaway java"
+match = "name:java cpu>90"
+for   = "30s"
+`)
+	// -swap-warn was given on the command line, so it wins.
+	if err := loadConfig(path, map[string]bool{"swap-warn": true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if tickInterval != 2*time.Second {
+		t.Errorf("refresh = %v, want 2s", tickInterval)
+	}
+	if defaultSort != SortMem || !defaultSortAsc {
+		t.Errorf("sort = %v asc=%v, want mem ascending (reversed)", defaultSort, defaultSortAsc)
+	}
+	if defaultFilter != "user:postgres" || defaultFilterMode != matchFuzzy {
+		t.Errorf("filter = %q (%s), want user:postgres (fuzzy)", defaultFilter, defaultFilterMode)
+	}
+	if len(defaultColumns) != 3 || defaultColumns[2] != SortMem {
+		t.Errorf("columns = %v, want pid, name, mem", defaultColumns)
+	}
+	if highCPUThresh != 80 || availWarnPct != 15 || swapWarnPct != 50 {
+		t.Errorf("thresholds high_cpu=%g avail_warn=%g swap_warn=%g, want 80, 15 and the flag's 50",
+			highCPUThresh, availWarnPct, swapWarnPct)
+	}
+	if colorAccent != "#5fafff" {
+		t.Errorf("accent = %q", colorAccent)
+	}
+	if keyTree != "T" || keyMark != " " {
+		t.Errorf("tree = %q, mark = %q", keyTree, keyMark)
+	}
+	if len(alertRules) != 1 || alertRules[0].name != "runaway java" || alertRules[0].dur != 30*time.Second || alertExec != "true" {
+		t.Errorf("alerts: rules %+v, exec %q", alertRules, alertExec)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct{ config, want string }{
+		{`refresh = "1ms"`, "refresh 1ms is outside"},
+		{`refresh = "soon"`, "soon"},
+		{`sort = "bogus"`, `sort: unknown column "bogus"`},
+		{`filter_mode = "glob"`, `unknown mode "glob"`},
+		{`filter = "cpu>"`, "filter:"},
+		{`columns = []`, "at least one column"},
+		{`colour = "red"`, `unknown setting "colour"`},
+		{"[thresholds]\navail_warn = 150", "thresholds.avail_warn: 150 is out of range"},
+		{"[colors]\nsparkle = \"1\"", `unknown colour "sparkle"`},
+		{"[colors]\naccent = \"red\"", "colors.accent"},
+		{"[keys]\nfrobnicate = \"x\"", `unknown action "frobnicate"`},
+		{"[keys]\ntree = \"q\"", `"q" is bound to both quit and tree`},
+		{"[keys]\ncolumn_up = \"s\"", "bound to both column_up and column_sort in the column chooser"},
+		{"[keys]\ndeny = \"3\"", "in the signal picker"},
+		{"[keys]\nfilter_mode = \"x\"", "couldn't be typed in the filter prompt"},
+		{"[keys]\nfilter_target = \"ctrl+w\"", "bound to both text editing and filter_target"},
+		{"[[alerts.rule]]\nmatch = \"cpu>>90\"", "alerts.rule[0]: match:"},
+		{"[[alerts.rule]]\nsystem = \"avail<5\"\nfor = \"later\"", "later"},
+	}
+	for _, tt := range tests {
+		restore := saveConfig()
+		err := loadConfig(writeConfig(t, tt.config), nil)
+		restore()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: err = %v, want %q", tt.config, err, tt.want)
+		}
+	}
+
+	if err := loadConfig(filepath.Join(t.TempDir(), "missing"), nil); err == nil {
+		t.Error("missing -config file: no error")
+	}
+}
+
+func TestLoadConfigMissingDefault(t *testing.T) {
+	defer saveConfig()()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	if err := loadConfig("", nil); err != nil {
+		t.Errorf("no config file at the default path: %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shirou/gopsutil/v3/net"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// connsMsg carries the sockets of one process for the connections view.
+type connsMsg struct {
+	PID   int32
+	Conns []net.ConnectionStat
+	Err   error
+}
+
+func fetchConns(pid int32) tea.Cmd {
+	return func() tea.Msg {
+		p, err := process.NewProcess(pid)
+		if err != nil {
+			return connsMsg{PID: pid, Err: err}
+		}
+		conns, err := p.Connections()
+		sortConns(conns)
+		return connsMsg{PID: pid, Conns: conns, Err: err}
+	}
+}
+
+// listeningPorts maps each PID to the TCP ports it listens on and the UDP
+// ports it has bound, for the port: filter. Sockets of other users'
+// processes are only attributed to a PID when gomon runs as root.
+func listeningPorts() map[int32][]uint32 {
+	conns, err := net.Connections("inet")
+	if err != nil {
+		return nil
+	}
+	out := map[int32][]uint32{}
+	seen := map[[2]uint32]bool{} // pid, port — tcp4 and tcp6 often both bind
+	for _, c := range conns {
+		if c.Pid == 0 || !isListening(c) {
+			continue
+		}
+		key := [2]uint32{uint32(c.Pid), c.Laddr.Port}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out[c.Pid] = append(out[c.Pid], c.Laddr.Port)
+	}
+	return out
+}
+
+// isListening reports whether c is a listening TCP socket or a UDP socket
+// bound to a local port with no fixed peer.
+func isListening(c net.ConnectionStat) bool {
+	switch c.Type {
+	case syscall.SOCK_STREAM:
+		return c.Status == "LISTEN"
+	case syscall.SOCK_DGRAM:
+		return c.Laddr.Port != 0 && c.Raddr.Port == 0
+	}
+	return false
+}
+
+// sortConns puts listening sockets first, then groups by protocol and
+// local port.
+func sortConns(conns []net.ConnectionStat) {
+	sort.SliceStable(conns, func(i, j int) bool {
+		a, b := conns[i], conns[j]
+		if la, lb := isListening(a), isListening(b); la != lb {
+			return la
+		}
+		if pa, pb := connProto(a), connProto(b); pa != pb {
+			return pa < pb
+		}
+		return a.Laddr.Port < b.Laddr.Port
+	})
+}
+
+// connProto names a socket's protocol the way ss(8) does: tcp, udp6, unix…
+func connProto(c net.ConnectionStat) string {
+	var proto string
+	switch c.Family {
+	case syscall.AF_UNIX:
+		return "unix"
+	case syscall.AF_INET, syscall.AF_INET6:
+		switch c.Type {
+		case syscall.SOCK_STREAM:
+			proto = "tcp"
+		case syscall.SOCK_DGRAM:
+			proto = "udp"
+		default:
+			proto = "raw"
+		}
+	default:
+		return "?"
+	}
+	if c.Family == syscall.AF_INET6 {
+		proto += "6"
+	}
+	return proto
+}
+
+// connAddr formats one end of a socket. Unix sockets carry their path in
+// IP; an unset address or port prints as "*".
+func connAddr(c net.ConnectionStat, a net.Addr) string {
+	if c.Family == syscall.AF_UNIX {
+		if a.IP == "" {
+			return "*"
+		}
+		return a.IP
+	}
+	ip, port := a.IP, fmt.Sprint(a.Port)
+	if ip == "" {
+		ip = "*"
+	} else if strings.Contains(ip, ":") {
+		ip = "[" + ip + "]"
+	}
+	if a.Port == 0 {
+		port = "*"
+	}
+	return ip + ":" + port
+}
+
+// ---------------------------------------------------------------------------
+// Key handling
+// ---------------------------------------------------------------------------
+
+// openConns switches to the connections view for the selected process.
+func (m *Model) openConns() tea.Cmd {
+	if len(m.visibleProc) == 0 {
+		return nil
+	}
+	row := m.visibleProc[m.cursor]
+	m.connsPID, m.connsName = row.PID, row.Name
+	m.conns, m.connsErr, m.connsLoaded = nil, nil, false
+	m.connsScroll = 0
+	m.mode = ModeConnections
+	return fetchConns(m.connsPID)
+}
+
+func (m Model) handleConnsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case keyEsc, keyQuit, keyConns:
+		m.mode = ModeNormal
+		m.conns = nil
+	case keyUp, keyVimUp:
+		scrollBody(&m.connsScroll, -1, len(m.conns), m.bodyHeight(listChrome))
+	case keyDown, keyVimDown:
+		scrollBody(&m.connsScroll, 1, len(m.conns), m.bodyHeight(listChrome))
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// ---------------------------------------------------------------------------
+// View
+// ---------------------------------------------------------------------------
+
+func (m *Model) renderConnsScreen() string {
+	var b strings.Builder
+	indent := "  "
+
+	b.WriteString(m.renderHeader())
+	b.WriteString("\n")
+	b.WriteString(m.renderSeparator())
+	b.WriteString("\n")
+
+	title := fmt.Sprintf("Connections — PID %d (%s)", m.connsPID, m.connsName)
+	if m.connsLoaded && m.connsErr == nil {
+		listening := 0
+		for _, c := range m.conns {
+			if isListening(c) {
+				listening++
+			}
+		}
+		title += fmt.Sprintf("  ·  %d sockets, %d listening", len(m.conns), listening)
+	}
+	b.WriteString(styleHelpTitle.Render(indent + title))
+	b.WriteString("\n")
+
+	// Address columns share what is left after proto, state and gaps.
+	addrW := (m.termWidth - len(indent) - 6 - 12 - 6) / 2
+	if addrW < 16 {
+		addrW = 16
+	}
+	row := func(proto, local, remote, state string) string {
+		return indent + padRight(proto, 6) + "  " + padRight(truncate(local, addrW), addrW) + "  " +
+			padRight(truncate(remote, addrW), addrW) + "  " + state
+	}
+	b.WriteString(styleColHeader.Render(row("PROTO", "LOCAL ADDRESS", "REMOTE ADDRESS", "STATE")))
+	b.WriteString("\n")
+
+	var lines []string
+	switch {
+	case m.connsErr != nil:
+		lines = append(lines, styleStatusError.Render(indent+"Error: "+m.connsErr.Error()))
+	case !m.connsLoaded:
+		lines = append(lines, styleStatusBar.Render(indent+"Loading…"))
+	case len(m.conns) == 0:
+		lines = append(lines, styleStatusBar.Render(indent+"No open sockets"))
+	}
+	for _, c := range m.conns {
+		state := c.Status
+		if state == "NONE" {
+			state = ""
+		}
+		line := row(connProto(c), connAddr(c, c.Laddr), connAddr(c, c.Raddr), state)
+		if isListening(c) {
+			line = styleCursor.Render(line)
+		} else {
+			line = styleHelpDesc.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	b.WriteString(renderScrollBody(lines, &m.connsScroll, m.bodyHeight(listChrome)))
+
+	b.WriteString(m.renderSeparator())
+	b.WriteString("\n")
+	b.WriteString(styleStatusBar.Render(indent + scrollHelp() + "  ·  listening sockets in green  ·  " +
+		keysLabel(keyEsc, keyQuit) + " back"))
+
+	return b.String()
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// ProcessDetail is everything shown in the detail pane for a single PID.
+// It is collected on demand, never as part of the per-tick snapshot.
+type ProcessDetail struct {
+	PID        int32
+	PPID       int32
+	ParentName string
+	Name       string
+	User       string
+	Cmdline    string
+	Exe        string
+	Cwd        string
+	StartTime  time.Time
+	Nice       int32
+	Status     string
+	NumFDs     int32
+	Rlimits    []process.RlimitStat
+	Environ    []string
+
+	// Cumulative disk I/O since the process started, and the rate since
+	// the previous detail sample (set by Update; zero on the first).
+	IO        process.IOCountersStat
+	IOAt      time.Time
+	ReadRate  float64 // bytes/s
+	WriteRate float64 // bytes/s
+
+	// Fields that could not be read (usually permission denied) are
+	// listed here so the view can print N/A instead of a zero value.
+	missing map[string]bool
+}
+
+// rlimitNames maps gopsutil resource IDs to their conventional names.
+var rlimitNames = map[int32]string{
+	process.RLIMIT_CPU:        "cpu",
+	process.RLIMIT_FSIZE:      "fsize",
+	process.RLIMIT_DATA:       "data",
+	process.RLIMIT_STACK:      "stack",
+	process.RLIMIT_CORE:       "core",
+	process.RLIMIT_RSS:        "rss",
+	process.RLIMIT_NPROC:      "nproc",
+	process.RLIMIT_NOFILE:     "nofile",
+	process.RLIMIT_MEMLOCK:    "memlock",
+	process.RLIMIT_AS:         "as",
+	process.RLIMIT_LOCKS:      "locks",
+	process.RLIMIT_SIGPENDING: "sigpending",
+	process.RLIMIT_MSGQUEUE:   "msgqueue",
+	process.RLIMIT_NICE:       "nice",
+	process.RLIMIT_RTPRIO:     "rtprio",
+	process.RLIMIT_RTTIME:     "rttime",
+}
+
+// rlimInfinity is RLIM_INFINITY as reported by gopsutil on Linux.
+const rlimInfinity = ^uint64(0)
+
+// CollectProcessDetail reads the full detail set for one PID.
+// Individual fields that fail are marked missing rather than failing the
+// whole call; only a vanished process is reported as an error.
+func CollectProcessDetail(pid int32) processDetailMsg {
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return processDetailMsg{PID: pid, Err: err}
+	}
+
+	d := ProcessDetail{PID: pid, missing: map[string]bool{}}
+	miss := func(field string, err error) bool {
+		if err != nil {
+			d.missing[field] = true
+			return true
+		}
+		return false
+	}
+
+	if name, err := p.Name(); !miss("name", err) {
+		d.Name = name
+	}
+	if user, err := p.Username(); !miss("user", err) {
+		d.User = user
+	}
+	if cmd, err := p.Cmdline(); !miss("cmdline", err) {
+		d.Cmdline = cmd
+	}
+	if exe, err := p.Exe(); !miss("exe", err) {
+		d.Exe = exe
+	}
+	if cwd, err := p.Cwd(); !miss("cwd", err) {
+		d.Cwd = cwd
+	}
+	if ms, err := p.CreateTime(); !miss("start", err) {
+		d.StartTime = time.UnixMilli(ms)
+	}
+	if ppid, err := p.Ppid(); !miss("ppid", err) {
+		d.PPID = ppid
+		if parent, err := process.NewProcess(ppid); err == nil {
+			d.ParentName, _ = parent.Name()
+		}
+	}
+	if nice, err := processNice(p); !miss("nice", err) {
+		d.Nice = nice
+	}
+	if st, err := p.Status(); !miss("status", err) {
+		d.Status = strings.Join(st, ",")
+	}
+	if n, err := p.NumFDs(); !miss("fds", err) {
+		d.NumFDs = n
+	}
+	if io, err := p.IOCounters(); !miss("io", err) {
+		d.IO = *io
+		d.IOAt = time.Now()
+	}
+	if lim, err := p.Rlimit(); !miss("rlimits", err) {
+		d.Rlimits = lim
+	}
+	if env, err := p.Environ(); !miss("environ", err) {
+		sort.Strings(env)
+		d.Environ = env
+	}
+
+	return processDetailMsg{PID: pid, Detail: &d}
+}
+
+// processNice returns the nice value in the usual -20..19 range.
+// On Linux gopsutil passes through the raw getpriority(2) syscall result,
+// which the kernel reports as 20-nice.
+func processNice(p *process.Process) (int32, error) {
+	n, err := p.Nice()
+	if err != nil {
+		return 0, err
+	}
+	if runtime.GOOS == "linux" {
+		n = 20 - n
+	}
+	return n, nil
+}
+
+// setIORates derives read/write rates from the previous sample of the same
+// process. Counters that went backwards (a recycled PID) give no rate.
+func (d *ProcessDetail) setIORates(prev *ProcessDetail) {
+	if prev == nil || prev.PID != d.PID || d.missing["io"] || prev.missing["io"] {
+		return
+	}
+	dt := d.IOAt.Sub(prev.IOAt).Seconds()
+	if dt <= 0 || d.IO.ReadBytes < prev.IO.ReadBytes || d.IO.WriteBytes < prev.IO.WriteBytes {
+		return
+	}
+	d.ReadRate = float64(d.IO.ReadBytes-prev.IO.ReadBytes) / dt
+	d.WriteRate = float64(d.IO.WriteBytes-prev.IO.WriteBytes) / dt
+}
+
+func fetchDetail(pid int32) tea.Cmd {
+	return func() tea.Msg {
+		return CollectProcessDetail(pid)
+	}
+}
+
+// detailCmd loads the detail pane from the live system, or from the
+// current frame when replaying a recording.
+func (m *Model) detailCmd(pid int32) tea.Cmd {
+	if r := m.replay; r != nil {
+		return func() tea.Msg { return r.Detail(pid) }
+	}
+	return fetchDetail(pid)
+}
+
+// ---------------------------------------------------------------------------
+// Key handling
+// ---------------------------------------------------------------------------
+
+func (m Model) handleDetailKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case keyEsc, keyQuit, keyEnter:
+		m.mode = ModeNormal
+		m.detail = nil
+	case keyUp, keyVimUp:
+		scrollBody(&m.detailScroll, -1, m.detailLineCount(), m.bodyHeight(pageChrome))
+	case keyDown, keyVimDown:
+		scrollBody(&m.detailScroll, 1, m.detailLineCount(), m.bodyHeight(pageChrome))
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// ---------------------------------------------------------------------------
+// View
+// ---------------------------------------------------------------------------
+
+func (m *Model) renderDetailScreen() string {
+	var b strings.Builder
+	indent := "  "
+
+	b.WriteString(m.renderHeader())
+	b.WriteString("\n")
+	b.WriteString(m.renderSeparator())
+	b.WriteString("\n")
+
+	title := fmt.Sprintf("Process %d", m.detailPID)
+	var lines []string
+	switch {
+	case m.detailErr != nil:
+		lines = append(lines, styleStatusError.Render(indent+"Error: "+m.detailErr.Error()))
+	case m.detail == nil:
+		lines = append(lines, styleStatusBar.Render(indent+"Loading…"))
+	default:
+		title = fmt.Sprintf("Process %d — %s", m.detail.PID, m.detail.Name)
+		lines = m.detailLines()
+	}
+	b.WriteString(styleHelpTitle.Render(indent + title))
+	b.WriteString("\n")
+
+	// Body scrolls between the title and the footer.
+	b.WriteString(renderScrollBody(lines, &m.detailScroll, m.bodyHeight(pageChrome)))
+
+	b.WriteString(m.renderSeparator())
+	b.WriteString("\n")
+	b.WriteString(styleStatusBar.Render(indent + scrollHelp() + "  ·  " + keysLabel(keyEsc, keyEnter, keyQuit) + " back"))
+
+	return b.String()
+}
+
+// detailLineCount is the number of lines the detail pane scrolls through.
+func (m *Model) detailLineCount() int {
+	if m.detail == nil {
+		return 0
+	}
+	return len(m.detailLines())
+}
+
+// detailLines flattens the detail struct into styled display lines,
+// wrapping long values to the terminal width.
+func (m *Model) detailLines() []string {
+	d := m.detail
+	indent := "    "
+	keyW := 12
+	valW := m.termWidth - len(indent) - keyW
+	if valW < 20 {
+		valW = 20
+	}
+
+	var out []string
+	field := func(key, name, val string) {
+		if d.missing[key] {
+			val = "N/A"
+		}
+		for i, chunk := range wrapText(val, valW) {
+			k := ""
+			if i == 0 {
+				k = name
+			}
+			out = append(out, indent+styleHelpKey.Render(padRight(k, keyW))+styleHelpDesc.Render(chunk))
+		}
+	}
+	section := func(title string) {
+		out = append(out, "", styleHelpSection.Render("  "+title))
+	}
+
+	section("Process")
+	field("cmdline", "Command", d.Cmdline)
+	field("exe", "Executable", d.Exe)
+	field("cwd", "Cwd", d.Cwd)
+	field("user", "User", d.User)
+	parent := fmt.Sprintf("%d", d.PPID)
+	if d.ParentName != "" {
+		parent += " (" + d.ParentName + ")"
+	}
+	field("ppid", "Parent", parent)
+	start := ""
+	if !d.StartTime.IsZero() {
+		start = d.StartTime.Format("2006-01-02 15:04:05") +
+			"  (" + time.Since(d.StartTime).Truncate(time.Second).String() + " ago)"
+	}
+	field("start", "Started", start)
+	field("status", "Status", d.Status)
+	field("nice", "Nice", fmt.Sprintf("%d", d.Nice))
+	field("fds", "Open files", fmt.Sprintf("%d", d.NumFDs))
+
+	section("Disk I/O (since start)")
+	ioLine := func(bytes, calls uint64, rate float64) string {
+		return fmt.Sprintf("%s in %d calls  ·  now %sB/s", formatBytes(bytes), calls, formatRate(rate))
+	}
+	field("io", "Read", ioLine(d.IO.ReadBytes, d.IO.ReadCount, d.ReadRate))
+	field("io", "Written", ioLine(d.IO.WriteBytes, d.IO.WriteCount, d.WriteRate))
+
+	section("Limits (soft / hard)")
+	if d.missing["rlimits"] {
+		out = append(out, indent+styleHelpDesc.Render("N/A"))
+	}
+	for _, r := range d.Rlimits {
+		name, ok := rlimitNames[r.Resource]
+		if !ok {
+			name = fmt.Sprintf("#%d", r.Resource)
+		}
+		val := formatRlimit(r.Soft) + " / " + formatRlimit(r.Hard)
+		out = append(out, indent+styleHelpKey.Render(padRight(name, keyW))+styleHelpDesc.Render(val))
+	}
+
+	section(fmt.Sprintf("Environment (%d)", len(d.Environ)))
+	if d.missing["environ"] {
+		out = append(out, indent+styleHelpDesc.Render("N/A"))
+	}
+	envW := m.termWidth - len(indent)
+	for _, kv := range d.Environ {
+		for _, chunk := range wrapText(kv, envW) {
+			out = append(out, indent+styleHelpDesc.Render(chunk))
+		}
+	}
+
+	return out
+}
+
+// formatBytes is formatRate for a byte count: "512B", "1.5MB".
+func formatBytes(n uint64) string {
+	return formatRate(float64(n)) + "B"
+}
+
+func formatRlimit(v uint64) string {
+	if v == rlimInfinity {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%d", v)
+}
+
+// wrapText splits s into chunks of at most width runes.
+func wrapText(s string, width int) []string {
+	runes := []rune(s)
+	if len(runes) <= width || width <= 0 {
+		return []string{s}
+	}
+	var out []string
+	for len(runes) > width {
+		out = append(out, string(runes[:width]))
+		runes = runes[width:]
+	}
+	return append(out, string(runes))
+}
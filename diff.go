@@ -0,0 +1,362 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// diffTop is how many of the biggest changes are listed per column.
+const diffTop = 10
+
+// diffColumns are the columns whose biggest changes a diff reports.
+var diffColumns = []SortColumn{SortCPU, SortMem, SortThreads}
+
+// snapshotDiff compares an older and a newer process list.
+type snapshotDiff struct {
+	Started []ProcessRow // only in the newer list
+	Exited  []ProcessRow // only in the older list
+	Changed []procDelta  // in both
+}
+
+// procDelta is one process as seen in both lists.
+type procDelta struct {
+	Old, New ProcessRow
+}
+
+// sameProcess reports whether two rows with the same PID describe the same
+// process rather than a recycled PID. Start times settle it when both
+// snapshots have them; otherwise the name has to match.
+func sameProcess(a, b ProcessRow) bool {
+	if !a.Started.IsZero() && !b.Started.IsZero() {
+		return a.Started.Equal(b.Started)
+	}
+	return a.Name == b.Name
+}
+
+func diffProcs(older, newer []ProcessRow) snapshotDiff {
+	byPID := make(map[int32]ProcessRow, len(older))
+	for _, r := range older {
+		byPID[r.PID] = r
+	}
+	var d snapshotDiff
+	kept := make(map[int32]bool, len(newer))
+	for _, r := range newer {
+		if o, ok := byPID[r.PID]; ok && sameProcess(o, r) {
+			d.Changed = append(d.Changed, procDelta{Old: o, New: r})
+			kept[r.PID] = true
+		} else {
+			d.Started = append(d.Started, r)
+		}
+	}
+	for _, r := range older {
+		if !kept[r.PID] {
+			d.Exited = append(d.Exited, r)
+		}
+	}
+	return d
+}
+
+// sort orders the started and exited lists with a table comparator.
+func (d *snapshotDiff) sort(less func(a, b ProcessRow) bool) {
+	sort.SliceStable(d.Started, func(i, j int) bool { return less(d.Started[i], d.Started[j]) })
+	sort.SliceStable(d.Exited, func(i, j int) bool { return less(d.Exited[i], d.Exited[j]) })
+}
+
+// delta is New minus Old in the fields of diffColumns.
+func (p procDelta) delta() ProcessRow {
+	return ProcessRow{
+		PID:     p.New.PID,
+		Name:    p.New.Name,
+		CPU:     p.New.CPU - p.Old.CPU,
+		MemMB:   p.New.MemMB - p.Old.MemMB,
+		Threads: p.New.Threads - p.Old.Threads,
+	}
+}
+
+// biggest returns up to n changed processes ranked by how far col moved in
+// either direction, largest first. The column's own comparator does the
+// ranking, applied to the absolute deltas; changes too small to show in the
+// column's format are left out.
+func (d snapshotDiff) biggest(col SortColumn, n int) []procDelta {
+	def := columnDef(col)
+	abs := func(p procDelta) ProcessRow {
+		r := p.delta()
+		r.CPU, r.MemMB = math.Abs(r.CPU), math.Abs(r.MemMB)
+		if r.Threads < 0 {
+			r.Threads = -r.Threads
+		}
+		return r
+	}
+	var out []procDelta
+	for _, p := range d.Changed {
+		if strings.Trim(def.format(nil, 0, abs(p)), "0.") != "" {
+			out = append(out, p)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return def.less(nil, abs(out[j]), abs(out[i])) })
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// diffReport lays out a diff as plain text: the started and exited
+// processes, then the biggest changes per column. heading styles the
+// section titles.
+func diffReport(d snapshotDiff, top int, heading func(string) string) string {
+	var b strings.Builder
+	list := func(title string, rows []ProcessRow) {
+		b.WriteString(heading(fmt.Sprintf("%s (%d)", title, len(rows))))
+		b.WriteString("\n")
+		if len(rows) > 0 {
+			writeProcTable(&b, rows)
+		}
+		b.WriteString("\n")
+	}
+	list("Started", d.Started)
+	list("Exited", d.Exited)
+
+	for _, col := range diffColumns {
+		def := columnDef(col)
+		rows := d.biggest(col, top)
+		b.WriteString(heading("Biggest " + def.title + " changes"))
+		b.WriteString("\n")
+		if len(rows) == 0 {
+			b.WriteString("none\n\n")
+			continue
+		}
+		nameW := len("NAME")
+		for _, p := range rows {
+			if n := len([]rune(p.New.Name)); n > nameW {
+				nameW = n
+			}
+		}
+		if nameW > colNameMax {
+			nameW = colNameMax
+		}
+		valW := def.width
+		if valW < len("BEFORE") {
+			valW = len("BEFORE")
+		}
+		fmt.Fprintf(&b, "%*s %s %*s %*s %*s\n", colPID, "PID", padRight("NAME", nameW),
+			valW, "BEFORE", valW, "AFTER", valW, "CHANGE")
+		for _, p := range rows {
+			change := def.format(nil, 0, p.delta())
+			if !strings.HasPrefix(change, "-") {
+				change = "+" + change
+			}
+			fmt.Fprintf(&b, "%*d %s %*s %*s %*s\n", colPID, p.New.PID,
+				padRight(truncate(p.New.Name, nameW), nameW),
+				valW, def.format(nil, 0, p.Old), valW, def.format(nil, 0, p.New), valW, change)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// ---------------------------------------------------------------------------
+// gomon diff
+// ---------------------------------------------------------------------------
+
+// runDiff implements `gomon diff old.json new.json` on snapshots written
+// by --batch -format json.
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("gomon diff", flag.ExitOnError)
+	top := fs.Int("top", diffTop, "changes to list per column")
+	sortBy := fs.String("sort", "cpu", "sort column for the started and exited lists")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: gomon diff [-top n] [-sort column] old.json new.json")
+		fmt.Fprintln(fs.Output(), "Compares the last snapshot in each file (from gomon -batch -format json).")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	col, ok := columnByKey(strings.ToLower(*sortBy))
+	if !ok {
+		return fmt.Errorf("unknown sort column %q (want one of %s)", *sortBy, columnKeys())
+	}
+
+	var snaps [2]batchSnapshot
+	for i, path := range fs.Args() {
+		s, err := readSnapshot(path)
+		if err != nil {
+			return err
+		}
+		snaps[i] = s
+	}
+	d := diffProcs(snaps[0].Procs, snaps[1].Procs)
+	m := Model{sortCol: col, sortAsc: col.defaultAsc()}
+	d.sort(m.compareRows)
+
+	for i, s := range snaps {
+		fmt.Printf("%-5s %s  %s  host: %s  procs: %d\n", []string{"old:", "new:"}[i],
+			fs.Arg(i), s.Time.Format("2006-01-02 15:04:05"), s.Hostname, len(s.Procs))
+	}
+	fmt.Println()
+	fmt.Println(diffReport(d, *top, func(s string) string { return s }))
+	return nil
+}
+
+// readSnapshot returns the last snapshot in an NDJSON file written by
+// --batch -format json.
+func readSnapshot(path string) (batchSnapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return batchSnapshot{}, err
+	}
+	defer f.Close()
+	dec := json.NewDecoder(f)
+	var last batchSnapshot
+	n := 0
+	for {
+		var s batchSnapshot
+		err := dec.Decode(&s)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return batchSnapshot{}, fmt.Errorf("%s: %v", path, err)
+		}
+		last = s
+		n++
+	}
+	if n == 0 {
+		return batchSnapshot{}, fmt.Errorf("%s: no snapshots (write one with gomon -batch -format json -n 1)", path)
+	}
+	return last, nil
+}
+
+// ---------------------------------------------------------------------------
+// Baseline in the TUI
+// ---------------------------------------------------------------------------
+
+// toggleBaseline marks the current snapshot as the baseline the table is
+// compared against, or clears it.
+func (m *Model) toggleBaseline() {
+	if m.baseline != nil {
+		m.baseline, m.baseNew, m.baseGone = nil, nil, nil
+		m.baseDiff = snapshotDiff{}
+		m.statusMsg = "baseline cleared"
+		return
+	}
+	m.baseline = append([]ProcessRow{}, m.allProcs...)
+	m.baselineAt = time.Now()
+	if m.replay != nil {
+		m.baselineAt = m.replay.frameTime()
+	}
+	m.applyFilterAndSort()
+	m.statusMsg = fmt.Sprintf("baseline marked (%d processes) — %s lists changes, %s clears",
+		len(m.baseline), keyLabel(keyBaseDiff), keyLabel(keyBaseline))
+}
+
+// updateBaseline compares the current snapshot with the baseline. Exited
+// processes that pass the filter are kept, in table order, for the rows
+// drawn below the live ones.
+func (m *Model) updateBaseline(less func(a, b ProcessRow) bool) {
+	m.baseDiff = diffProcs(m.baseline, m.allProcs)
+	m.baseDiff.sort(less)
+	m.baseNew = make(map[int32]bool, len(m.baseDiff.Started))
+	for _, r := range m.baseDiff.Started {
+		m.baseNew[r.PID] = true
+	}
+	var gone []ProcessRow
+	for i := range m.baseDiff.Exited {
+		if m.filterQuery.Match(&m.baseDiff.Exited[i]) {
+			gone = append(gone, m.baseDiff.Exited[i])
+		}
+	}
+	m.baseGone = gone
+}
+
+// renderExitedRow draws a process that has exited since the baseline in
+// the table's columns, struck through and marked −.
+func (m *Model) renderExitedRow(idx int, row ProcessRow, cols []SortColumn, widths []int) string {
+	cells := make([]string, len(cols))
+	for c, id := range cols {
+		def := columnDef(id)
+		text := truncate(def.format(m, idx, row), widths[c])
+		if def.right {
+			text = padLeft(text, widths[c])
+		} else {
+			text = padRight(text, widths[c])
+		}
+		cells[c] = styleRowExited.Render(text)
+	}
+	return styleRowExited.Render("−") + strings.Join(cells, styleBorder.Render(" │ "))
+}
+
+// baselineSummary is the header note while a baseline is set.
+func (m *Model) baselineSummary() string {
+	return fmt.Sprintf("+%d −%d since %s", len(m.baseDiff.Started), len(m.baseDiff.Exited),
+		m.baselineAt.Format("15:04:05"))
+}
+
+func (m Model) handleDiffKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case keyEsc, keyQuit, keyBaseDiff:
+		m.mode = ModeNormal
+	case keyUp, keyVimUp:
+		scrollBody(&m.diffScroll, -1, len(m.diffLines()), m.bodyHeight(pageChrome))
+	case keyDown, keyVimDown:
+		scrollBody(&m.diffScroll, 1, len(m.diffLines()), m.bodyHeight(pageChrome))
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// ---------------------------------------------------------------------------
+// View
+// ---------------------------------------------------------------------------
+
+func (m *Model) diffLines() []string {
+	// Headings are styled after truncation, so escapes are never cut.
+	headings := map[string]bool{}
+	report := diffReport(m.baseDiff, diffTop, func(s string) string {
+		headings[s] = true
+		return s
+	})
+	lines := strings.Split(report, "\n")
+	for i, l := range lines {
+		l = "  " + truncate(l, m.termWidth-2)
+		if headings[strings.TrimSpace(l)] {
+			l = styleHelpSection.Render(l)
+		}
+		lines[i] = l
+	}
+	return lines
+}
+
+func (m *Model) renderDiffScreen() string {
+	var b strings.Builder
+	indent := "  "
+
+	b.WriteString(m.renderHeader())
+	b.WriteString("\n")
+	b.WriteString(m.renderSeparator())
+	b.WriteString("\n")
+	b.WriteString(styleHelpTitle.Render(indent + "Changes since the baseline at " +
+		m.baselineAt.Format("15:04:05")))
+	b.WriteString("\n")
+
+	b.WriteString(renderScrollBody(m.diffLines(), &m.diffScroll, m.bodyHeight(pageChrome)))
+
+	b.WriteString(m.renderSeparator())
+	b.WriteString("\n")
+	b.WriteString(styleStatusBar.Render(indent + scrollHelp() + "  ·  " + keysLabel(keyEsc, keyQuit) + " back"))
+
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiffProcs(t *testing.T) {
+	t0 := time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC)
+	older := []ProcessRow{
+		{PID: 10, Name: "db", CPU: 5, MemMB: 100, Threads: 4, Started: t0},
+		{PID: 11, Name: "web", CPU: 50, MemMB: 300, Threads: 8},
+		{PID: 12, Name: "cron", CPU: 0, MemMB: 2, Threads: 1},
+		{PID: 13, Name: "job", CPU: 1, MemMB: 10, Threads: 1, Started: t0},
+	}
+	newer := []ProcessRow{
+		{PID: 10, Name: "db", CPU: 35, MemMB: 110, Threads: 4, Started: t0},
+		{PID: 11, Name: "web", CPU: 40, MemMB: 900, Threads: 9},
+		{PID: 12, Name: "cron", CPU: 0.001, MemMB: 2, Threads: 1},
+		// Same PID and name, later start: the PID was recycled.
+		{PID: 13, Name: "job", CPU: 1, MemMB: 10, Threads: 1, Started: t0.Add(time.Minute)},
+		{PID: 14, Name: "new", CPU: 2},
+	}
+	d := diffProcs(older, newer)
+
+	pids := func(rows []ProcessRow) []int32 {
+		var out []int32
+		for _, r := range rows {
+			out = append(out, r.PID)
+		}
+		return out
+	}
+	if got := pids(d.Started); len(got) != 2 || got[0] != 13 || got[1] != 14 {
+		t.Errorf("started = %v, want [13 14]", got)
+	}
+	if got := pids(d.Exited); len(got) != 1 || got[0] != 13 {
+		t.Errorf("exited = %v, want [13]", got)
+	}
+
+	// Ranked by size of the change whichever way it went; cron's change
+	// is too small to show at CPU%'s two decimals.
+	cpu := d.biggest(SortCPU, 10)
+	if len(cpu) != 2 || cpu[0].New.PID != 10 || cpu[1].New.PID != 11 {
+		t.Errorf("biggest CPU changes = %+v, want db then web", cpu)
+	}
+	if mem := d.biggest(SortMem, 1); len(mem) != 1 || mem[0].New.PID != 11 {
+		t.Errorf("biggest memory change = %+v, want web", mem)
+	}
+	if thr := d.biggest(SortThreads, 10); len(thr) != 1 || thr[0].delta().Threads != 1 {
+		t.Errorf("thread changes = %+v, want web +1", thr)
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// fdWarnPct is the share of the RLIMIT_NOFILE soft limit at which the FDS
+// cell turns red. Overridable from the config file.
+var fdWarnPct = 80.0
+
+// nearFDLimit reports whether r has used fdWarnPct of its descriptor limit.
+func nearFDLimit(r ProcessRow) bool {
+	return r.FDLimit > 0 && float64(r.FDs) >= float64(r.FDLimit)*fdWarnPct/100
+}
+
+// nofileLimit returns p's RLIMIT_NOFILE soft limit, or 0 when it is
+// unlimited or can't be read (other users' processes, non-Linux systems).
+func nofileLimit(p *process.Process) uint64 {
+	lims, err := p.Rlimit()
+	if err != nil {
+		return 0
+	}
+	for _, l := range lims {
+		if l.Resource == process.RLIMIT_NOFILE && l.Soft != rlimInfinity {
+			return l.Soft
+		}
+	}
+	return 0
+}
+
+// filesMsg carries the open files of one process for the files view.
+type filesMsg struct {
+	PID   int32
+	Files []process.OpenFilesStat
+	Limit uint64
+	Err   error
+}
+
+func fetchFiles(pid int32) tea.Cmd {
+	return func() tea.Msg {
+		p, err := process.NewProcess(pid)
+		if err != nil {
+			return filesMsg{PID: pid, Err: err}
+		}
+		files, err := p.OpenFiles()
+		sort.Slice(files, func(i, j int) bool { return files[i].Fd < files[j].Fd })
+		return filesMsg{PID: pid, Files: files, Limit: nofileLimit(p), Err: err}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Key handling
+// ---------------------------------------------------------------------------
+
+// openFiles switches to the open-files view for the selected process.
+func (m *Model) openFiles() tea.Cmd {
+	if len(m.visibleProc) == 0 {
+		return nil
+	}
+	row := m.visibleProc[m.cursor]
+	m.filesPID, m.filesName = row.PID, row.Name
+	m.files, m.filesLimit, m.filesErr, m.filesLoaded = nil, 0, nil, false
+	m.filesScroll = 0
+	m.mode = ModeFiles
+	return fetchFiles(m.filesPID)
+}
+
+func (m Model) handleFilesKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case keyEsc, keyQuit, keyFiles:
+		m.mode = ModeNormal
+		m.files = nil
+	case keyUp, keyVimUp:
+		scrollBody(&m.filesScroll, -1, len(m.files), m.bodyHeight(listChrome))
+	case keyDown, keyVimDown:
+		scrollBody(&m.filesScroll, 1, len(m.files), m.bodyHeight(listChrome))
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// ---------------------------------------------------------------------------
+// View
+// ---------------------------------------------------------------------------
+
+func (m *Model) renderFilesScreen() string {
+	var b strings.Builder
+	indent := "  "
+
+	b.WriteString(m.renderHeader())
+	b.WriteString("\n")
+	b.WriteString(m.renderSeparator())
+	b.WriteString("\n")
+
+	title := fmt.Sprintf("Open files — PID %d (%s)", m.filesPID, m.filesName)
+	if m.filesLoaded && m.filesErr == nil {
+		count := fmt.Sprintf("%d descriptors", len(m.files))
+		if m.filesLimit > 0 {
+			count += fmt.Sprintf(" of %d allowed", m.filesLimit)
+			r := ProcessRow{FDs: int32(len(m.files)), FDLimit: m.filesLimit}
+			if nearFDLimit(r) {
+				count = styleCellWarn.Render(count)
+			}
+		}
+		title = styleHelpTitle.Render(indent+title+"  ·  ") + count
+	} else {
+		title = styleHelpTitle.Render(indent + title)
+	}
+	b.WriteString(title)
+	b.WriteString("\n")
+	b.WriteString(styleColHeader.Render(indent + padLeft("FD", 6) + "  PATH"))
+	b.WriteString("\n")
+
+	var lines []string
+	switch {
+	case m.filesErr != nil:
+		lines = append(lines, styleStatusError.Render(indent+"Error: "+m.filesErr.Error()))
+	case !m.filesLoaded:
+		lines = append(lines, styleStatusBar.Render(indent+"Loading…"))
+	case len(m.files) == 0:
+		lines = append(lines, styleStatusBar.Render(indent+"No open files"))
+	}
+	pathW := m.termWidth - len(indent) - 8
+	for _, f := range m.files {
+		lines = append(lines, indent+styleHelpKey.Render(padLeft(fmt.Sprint(f.Fd), 6))+"  "+
+			styleHelpDesc.Render(truncate(f.Path, pathW)))
+	}
+
+	b.WriteString(renderScrollBody(lines, &m.filesScroll, m.bodyHeight(listChrome)))
+
+	b.WriteString(m.renderSeparator())
+	b.WriteString("\n")
+	b.WriteString(styleStatusBar.Render(indent + scrollHelp() + "  ·  " + keysLabel(keyEsc, keyQuit) + " back"))
+
+	return b.String()
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	coreBarWidth  = 10
+	coreCellWidth = 24 // "NN [||||||||||] 100.0%" plus gap
+	memBarWidth   = 30
+	swapBarWidth  = 10
+	// System CPU/RAM history graphs on the header lines
+	headerGraphWidth = 20
+)
+
+// Memory pressure thresholds, in percent. The header turns red when
+// available memory drops below availWarnPct or swap use rises above
+// swapWarnPct. Overridable from the command line.
+var (
+	availWarnPct = 10.0
+	swapWarnPct  = 50.0
+)
+
+// headerHeight returns how many lines renderHeader produces at the current
+// terminal width: the summary lines plus however many rows of per-core bars.
+func (m *Model) headerHeight() int {
+	return 3 + m.coreRows()
+}
+
+// coresPerRow is how many per-core bars fit side by side.
+func (m *Model) coresPerRow() int {
+	n := (m.termWidth - 2) / coreCellWidth
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+func (m *Model) coreRows() int {
+	n := len(m.sysStats.CPUPerCore)
+	if n == 0 {
+		return 0
+	}
+	per := m.coresPerRow()
+	return (n + per - 1) / per
+}
+
+func (m *Model) renderHeader() string {
+	mem := fmt.Sprintf("%.1f / %.1f GB", m.sysStats.MemUsed, m.sysStats.MemTotal)
+	line := fmt.Sprintf(
+		"%s   host: %s   uptime: %s   RAM: %s   refresh: %s",
+		styleHeaderLabel.Render("gomon"),
+		styleHeaderValue.Render(m.sysStats.Hostname),
+		styleHeaderValue.Render(m.sysStats.Uptime),
+		styleHeaderValue.Render(mem),
+		styleHeaderValue.Render(m.refresh.String()),
+	)
+	if m.baseline != nil {
+		line += "   baseline: " + styleHeaderValue.Render(m.baselineSummary())
+	}
+	if m.replay != nil {
+		line += "   replay: " + styleHeaderValue.Render(m.replay.status())
+	}
+	if m.paused {
+		line += "   " + stylePaused.Render(fmt.Sprintf("PAUSED (%s step)", keyLabel(keyStep)))
+	}
+	lines := []string{line, m.renderMemLine(), m.renderCPULine()}
+	lines = append(lines, m.renderCoreBars()...)
+
+	// Clip (rather than wrap) to the padded width, so a narrow terminal
+	// can't push the table down by growing the header.
+	clip := lipgloss.NewStyle().MaxWidth(m.termWidth - 2)
+	for i, l := range lines {
+		lines[i] = styleHeader.Width(m.termWidth).Render(clip.Render(l))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderMemLine draws a segmented RAM bar (used · buffers · cache · free)
+// followed by available, dirty/writeback and a swap bar. On narrow
+// terminals dirty/writeback, then the graph, then swap are left out.
+func (m *Model) renderMemLine() string {
+	s := m.sysStats
+	availLow := s.MemTotal > 0 && s.MemAvail/s.MemTotal*100 < availWarnPct
+	swapHigh := s.SwapTotal > 0 && s.SwapUsed/s.SwapTotal*100 > swapWarnPct
+
+	usedStyle := styleMemUsed
+	availStyle := styleHeaderValue
+	if availLow {
+		usedStyle, availStyle = styleBarHigh, styleBarHigh
+	}
+
+	var segs []barSegment
+	if s.MemTotal > 0 {
+		segs = []barSegment{
+			{s.MemUsed / s.MemTotal, "█", usedStyle},
+			{s.MemBuffers / s.MemTotal, "▓", styleMemBuffers},
+			{s.MemCached / s.MemTotal, "▒", styleMemCached},
+		}
+	}
+
+	const mb = 1024
+	graph := styleMemUsed.Render(sparkline(m.sysMemHist.last(headerGraphWidth), headerGraphWidth, 100)) + " "
+	core := segmentedBar(memBarWidth, segs) +
+		fmt.Sprintf(" used %s  buf %s  cache %s  avail %s",
+			usedStyle.Render(fmt.Sprintf("%.1f", s.MemUsed)),
+			styleMemBuffers.Render(fmt.Sprintf("%.1f", s.MemBuffers)),
+			styleMemCached.Render(fmt.Sprintf("%.1f", s.MemCached)),
+			availStyle.Render(fmt.Sprintf("%.1f GB", s.MemAvail)))
+	dirty := fmt.Sprintf("  dirty %s  wb %s",
+		styleHeaderValue.Render(fmt.Sprintf("%.0f MB", s.MemDirty*mb)),
+		styleHeaderValue.Render(fmt.Sprintf("%.0f MB", s.MemWriteback*mb)))
+
+	swap := ""
+	if s.SwapTotal > 0 {
+		swapStyle := styleMemUsed
+		if swapHigh {
+			swapStyle = styleBarHigh
+		}
+		swap = "   " + styleHeaderLabel.Render("Swap") + " " +
+			segmentedBar(swapBarWidth, []barSegment{{s.SwapUsed / s.SwapTotal, "█", swapStyle}}) +
+			" " + swapStyle.Render(fmt.Sprintf("%.1f / %.1f GB", s.SwapUsed, s.SwapTotal))
+	}
+
+	label := styleHeaderLabel.Render("Mem") + " "
+	avail := m.termWidth - 2 // styleHeader padding
+	for _, line := range []string{
+		label + graph + core + dirty + swap,
+		label + graph + core + swap,
+		label + core + swap,
+	} {
+		if lipgloss.Width(line) <= avail {
+			return line
+		}
+	}
+	return label + core
+}
+
+// barSegment is one coloured run in a segmentedBar, as a fraction of the
+// bar's full width.
+type barSegment struct {
+	frac  float64
+	glyph string
+	style lipgloss.Style
+}
+
+// segmentedBar draws "[" + coloured segments + padding + "]". Segments are
+// laid out left to right and clipped to width.
+func segmentedBar(width int, segs []barSegment) string {
+	var b strings.Builder
+	b.WriteString(styleHeaderValue.Render("["))
+	used := 0
+	for _, sg := range segs {
+		n := int(sg.frac*float64(width) + 0.5)
+		if n > width-used {
+			n = width - used
+		}
+		if n <= 0 {
+			continue
+		}
+		b.WriteString(sg.style.Render(strings.Repeat(sg.glyph, n)))
+		used += n
+	}
+	b.WriteString(styleBarEmpty.Render(strings.Repeat(" ", width-used)))
+	b.WriteString(styleHeaderValue.Render("]"))
+	return b.String()
+}
+
+// renderCPULine shows total CPU%, load averages, task counts and the
+// per-state counts. On narrow terminals the graph, then the task counts
+// are left out so the state counts stay visible.
+func (m *Model) renderCPULine() string {
+	s := m.sysStats
+	label := styleHeaderLabel.Render("CPU") + " " +
+		barStyle(s.CPUTotal).Render(fmt.Sprintf("%5.1f%%", s.CPUTotal))
+	graph := " " + styleBarLow.Render(sparkline(m.sysCPUHist.last(headerGraphWidth), headerGraphWidth, 100))
+	load := ""
+	if s.HasLoad {
+		load = "   load: " + styleHeaderValue.Render(
+			fmt.Sprintf("%.2f %.2f %.2f", s.Load1, s.Load5, s.Load15))
+	}
+	tasks := ""
+	if s.TasksTotal > 0 {
+		tasks = "   tasks: " + styleHeaderValue.Render(
+			fmt.Sprintf("%d running / %d total", s.TasksRun, s.TasksTotal))
+	}
+	states := ""
+	if counts := m.renderStateCounts(); counts != "" {
+		states = "   states: " + counts
+	}
+
+	avail := m.termWidth - 2 // styleHeader padding
+	for _, line := range []string{
+		label + graph + load + tasks + states,
+		label + load + tasks + states,
+		label + load + states,
+	} {
+		if lipgloss.Width(line) <= avail {
+			return line
+		}
+	}
+	return label + states
+}
+
+// stateOrder is the order of the per-state counts in the header.
+var stateOrder = []string{"R", "S", "D", "Z", "T", "I"}
+
+// renderStateCounts counts processes by state, e.g. "R 3 S 210 D 1 Z 2".
+// States with no processes are left out; D and Z are drawn in their
+// warning colours.
+func (m *Model) renderStateCounts() string {
+	counts := map[string]int{}
+	for _, p := range m.allProcs {
+		counts[p.State]++
+	}
+	var parts []string
+	for _, st := range stateOrder {
+		n := counts[st]
+		if n == 0 {
+			continue
+		}
+		style := styleHeaderValue
+		switch st {
+		case "Z":
+			style = styleStateZombie
+		case "D":
+			style = styleStateBlocked
+		}
+		parts = append(parts, style.Render(fmt.Sprintf("%s %d", st, n)))
+	}
+	return strings.Join(parts, styleHeaderValue.Render(" "))
+}
+
+// renderCoreBars lays out one utilisation bar per logical core, wrapping
+// onto as many rows as the terminal width requires.
+func (m *Model) renderCoreBars() []string {
+	cores := m.sysStats.CPUPerCore
+	per := m.coresPerRow()
+
+	var rows []string
+	for start := 0; start < len(cores); start += per {
+		var b strings.Builder
+		for i := start; i < start+per && i < len(cores); i++ {
+			pct := cores[i]
+			filled := int(pct/100*coreBarWidth + 0.5)
+			if filled > coreBarWidth {
+				filled = coreBarWidth
+			}
+			if filled < 0 {
+				filled = 0
+			}
+			style := barStyle(pct)
+			b.WriteString(styleHeaderLabel.Render(fmt.Sprintf("%2d", i)))
+			b.WriteString(styleHeaderValue.Render(" ["))
+			b.WriteString(style.Render(strings.Repeat("|", filled)))
+			b.WriteString(styleBarEmpty.Render(strings.Repeat(" ", coreBarWidth-filled)))
+			b.WriteString(styleHeaderValue.Render("]"))
+			b.WriteString(style.Render(fmt.Sprintf("%6.1f%%", pct)))
+			b.WriteString(styleHeaderValue.Render("  "))
+		}
+		rows = append(rows, b.String())
+	}
+	return rows
+}
+
+// barStyle picks a colour for a utilisation percentage.
+func barStyle(pct float64) lipgloss.Style {
+	switch {
+	case pct >= 80:
+		return styleBarHigh
+	case pct >= 50:
+		return styleBarMid
+	default:
+		return styleBarLow
+	}
+}
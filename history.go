@@ -0,0 +1,119 @@
+package main
+
+import "time"
+
+// historyLen is how many samples each ring buffer keeps — one minute at the
+// default tick interval.
+const historyLen = 60
+
+// ring is a fixed-capacity FIFO of float64 samples; pushing to a full ring
+// overwrites the oldest sample.
+type ring struct {
+	buf   []float64
+	start int
+	n     int
+}
+
+func newRing(capacity int) *ring {
+	return &ring{buf: make([]float64, capacity)}
+}
+
+func (r *ring) push(v float64) {
+	if r.n < len(r.buf) {
+		r.buf[(r.start+r.n)%len(r.buf)] = v
+		r.n++
+		return
+	}
+	r.buf[r.start] = v
+	r.start = (r.start + 1) % len(r.buf)
+}
+
+// last returns up to k most recent samples, oldest first.
+func (r *ring) last(k int) []float64 {
+	if k > r.n {
+		k = r.n
+	}
+	out := make([]float64, k)
+	for i := 0; i < k; i++ {
+		out[i] = r.buf[(r.start+r.n-k+i)%len(r.buf)]
+	}
+	return out
+}
+
+// procHistory holds the per-PID samples behind the sparkline columns, and
+// the name and start time of the process they were taken from.
+type procHistory struct {
+	cpu     *ring
+	mem     *ring
+	name    string
+	started time.Time
+}
+
+// recordProcHistory appends this tick's samples for every process and drops
+// history for PIDs that have exited. A PID that now belongs to a different
+// process (see sameProcess) starts a new history.
+func (m *Model) recordProcHistory(procs []ProcessRow) {
+	live := make(map[int32]struct{}, len(procs))
+	for _, p := range procs {
+		live[p.PID] = struct{}{}
+		h, ok := m.procHist[p.PID]
+		if !ok || !sameProcess(ProcessRow{Name: h.name, Started: h.started}, p) {
+			h = &procHistory{cpu: newRing(historyLen), mem: newRing(historyLen), name: p.Name, started: p.Started}
+			m.procHist[p.PID] = h
+		}
+		h.cpu.push(p.CPU)
+		h.mem.push(p.MemMB)
+	}
+	for pid := range m.procHist {
+		if _, ok := live[pid]; !ok {
+			delete(m.procHist, pid)
+		}
+	}
+}
+
+// recordSysHistory appends system-wide CPU% and RAM% for the header graph.
+func (m *Model) recordSysHistory(s sysStatsMsg) {
+	m.sysCPUHist.push(s.CPUTotal)
+	memPct := 0.0
+	if s.MemTotal > 0 {
+		memPct = s.MemUsed / s.MemTotal * 100
+	}
+	m.sysMemHist.push(memPct)
+}
+
+var sparkTicks = []rune(" ▁▂▃▄▅▆▇█")
+
+// sparkline renders samples as a right-aligned bar graph of the given width.
+// Values are scaled against ceil, or against the largest sample if that is
+// bigger (multi-core processes can exceed 100% CPU).
+func sparkline(samples []float64, width int, ceil float64) string {
+	if len(samples) > width {
+		samples = samples[len(samples)-width:]
+	}
+	max := ceil
+	for _, v := range samples {
+		if v > max {
+			max = v
+		}
+	}
+
+	out := make([]rune, 0, width)
+	for i := len(samples); i < width; i++ {
+		out = append(out, ' ')
+	}
+	top := len(sparkTicks) - 1
+	for _, v := range samples {
+		idx := 0
+		if max > 0 && v > 0 {
+			idx = int(v/max*float64(top) + 0.5)
+			if idx == 0 {
+				idx = 1 // keep any non-zero activity visible
+			}
+			if idx > top {
+				idx = top
+			}
+		}
+		out = append(out, sparkTicks[idx])
+	}
+	return string(out)
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProcHistoryResetsOnPIDReuse(t *testing.T) {
+	t0 := time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC)
+	m := NewModel(&fakeCollector{})
+	m.recordProcHistory([]ProcessRow{{PID: 42, Name: "build", CPU: 90, Started: t0}})
+	m.recordProcHistory([]ProcessRow{{PID: 42, Name: "build", CPU: 80, Started: t0}})
+	if n := m.procHist[42].cpu.n; n != 2 {
+		t.Fatalf("%d samples for a steady process, want 2", n)
+	}
+
+	// Same PID, later start: a new process that must not inherit the
+	// sparkline of the one before it.
+	m.recordProcHistory([]ProcessRow{{PID: 42, Name: "build", CPU: 1, Started: t0.Add(time.Second)}})
+	if got := m.procHist[42].cpu.last(historyLen); len(got) != 1 || got[0] != 1 {
+		t.Errorf("history after PID reuse = %v, want just the new process's sample", got)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package main
+
+import (
+	"context"
+	"os/exec"
+)
+
+// shellCommand runs s with /bin/sh, so alert hooks can use pipes, quoting
+// and the GOMON_ALERT_* variables.
+func shellCommand(ctx context.Context, s string) *exec.Cmd {
+	return exec.CommandContext(ctx, "/bin/sh", "-c", s)
+}
@@ -0,0 +1,13 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"os/exec"
+)
+
+// shellCommand runs s with cmd.exe; hooks read the alert as %GOMON_ALERT_*%.
+func shellCommand(ctx context.Context, s string) *exec.Cmd {
+	return exec.CommandContext(ctx, "cmd", "/C", s)
+}
@@ -1,28 +1,269 @@
 package main
 
-// Keybinding string constants used in Update and the help footer.
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+// Keybinding strings used in Update and the help footer. They are variables
+// so the [keys] table of the config file can remap them.
+var (
+	keyQuit         = "q"
+	keyUp           = "up"
+	keyDown         = "down"
+	keyVimUp        = "k"
+	keyVimDown      = "j"
+	keyFilter       = "/"
+	keyEsc          = "esc"
+	keyTab          = "tab"
+	keyDel          = "delete"
+	keyKill         = "K"
+	keyConfirmY     = "y"
+	keyConfirmN     = "n"
+	keyEnter        = "enter"
+	keySortPID      = "1"
+	keySortName     = "2"
+	keySortCPU      = "3"
+	keySortMem      = "4"
+	keySortStatus   = "5"
+	keySortUser     = "6"
+	keyHelp         = "?"
+	keyTree         = "t"
+	keyCollapse     = "left"
+	keyExpand       = "right"
+	keyVimCollapse  = "h"
+	keyVimExpand    = "l"
+	keyMark         = " "
+	keyMarkAll      = "a"
+	keyMarkInvert   = "i"
+	keyColumns      = "c"
+	keyFaster       = "+"
+	keySlower       = "-"
+	keyPause        = "p"
+	keyStep         = "."
+	keyRenice       = "r"
+	keySuspend      = "z"
+	keyResume       = "Z"
+	keyKillTree     = "X"
+	keyConns        = "n"
+	keyFiles        = "o"
+	keySeekBack     = "["
+	keySeekFwd      = "]"
+	keySeekBackFar  = "{"
+	keySeekFwdFar   = "}"
+	keyBaseline     = "b"
+	keyBaseDiff     = "B"
+	keyAlerts       = "A"
+	keyMoveUp       = "K"      // column chooser: move column up
+	keyMoveDown     = "J"      // column chooser: move column down
+	keySortBy       = "s"      // column chooser: sort by highlighted column
+	keyFilterMode   = "tab"    // filter mode: cycle substring/regex/fuzzy
+	keyFilterTarget = "ctrl+t" // filter mode: match name or command line
+)
+
+// keyScope is a set of modes a key is bound in. Within each mode keys must
+// not collide; the full-screen views (help, details, sockets, files, diff,
+// alerts) only use Normal-mode keys, so the Normal check covers them.
+type keyScope uint8
+
 const (
-	keyQuit      = "q"
-	keyUp        = "up"
-	keyDown      = "down"
-	keyVimUp     = "k"
-	keyVimDown   = "j"
-	keyFilter    = "/"
-	keyEsc       = "esc"
-	keyTab       = "tab"
-	keyDel       = "delete"
-	keyKill      = "K"
-	keyConfirmY  = "y"
-	keyConfirmN  = "n"
-	keyEnter     = "enter"
-	keySortPID   = "1"
-	keySortName  = "2"
-	keySortCPU   = "3"
-	keySortMem   = "4"
-	keySortStatus = "5"
-	keySortUser  = "6"
-	keyHelp      = "?"
+	scopeNormal  keyScope = 1 << iota
+	scopeColumns          // column chooser
+	scopeSignal           // signal picker
+	scopeInput            // filter and renice prompts
 )
 
+// keyBinding names a remappable key for the config file.
+type keyBinding struct {
+	name  string
+	key   *string
+	scope keyScope
+}
+
+var keyBindings = []keyBinding{
+	{"quit", &keyQuit, scopeNormal | scopeColumns},
+	{"up", &keyUp, scopeNormal | scopeColumns | scopeSignal},
+	{"down", &keyDown, scopeNormal | scopeColumns | scopeSignal},
+	{"vim_up", &keyVimUp, scopeNormal | scopeColumns | scopeSignal},
+	{"vim_down", &keyVimDown, scopeNormal | scopeColumns | scopeSignal},
+	{"filter", &keyFilter, scopeNormal},
+	{"cancel", &keyEsc, scopeNormal | scopeColumns | scopeSignal | scopeInput},
+	{"sort_next", &keyTab, scopeNormal},
+	{"signal", &keyDel, scopeNormal},
+	{"signal_alt", &keyKill, scopeNormal},
+	{"kill_tree", &keyKillTree, scopeNormal},
+	{"confirm", &keyConfirmY, scopeSignal},
+	{"deny", &keyConfirmN, scopeSignal},
+	{"select", &keyEnter, scopeNormal | scopeColumns | scopeSignal | scopeInput},
+	{"sort_pid", &keySortPID, scopeNormal},
+	{"sort_name", &keySortName, scopeNormal},
+	{"sort_cpu", &keySortCPU, scopeNormal},
+	{"sort_mem", &keySortMem, scopeNormal},
+	{"sort_threads", &keySortStatus, scopeNormal},
+	{"sort_user", &keySortUser, scopeNormal},
+	{"help", &keyHelp, scopeNormal},
+	{"tree", &keyTree, scopeNormal},
+	{"collapse", &keyCollapse, scopeNormal},
+	{"expand", &keyExpand, scopeNormal},
+	{"vim_collapse", &keyVimCollapse, scopeNormal},
+	{"vim_expand", &keyVimExpand, scopeNormal},
+	{"mark", &keyMark, scopeNormal | scopeColumns},
+	{"mark_all", &keyMarkAll, scopeNormal},
+	{"mark_invert", &keyMarkInvert, scopeNormal},
+	{"columns", &keyColumns, scopeNormal | scopeColumns},
+	{"connections", &keyConns, scopeNormal},
+	{"files", &keyFiles, scopeNormal},
+	{"seek_back", &keySeekBack, scopeNormal},
+	{"seek_forward", &keySeekFwd, scopeNormal},
+	{"seek_back_far", &keySeekBackFar, scopeNormal},
+	{"seek_forward_far", &keySeekFwdFar, scopeNormal},
+	{"baseline", &keyBaseline, scopeNormal},
+	{"baseline_diff", &keyBaseDiff, scopeNormal},
+	{"alerts", &keyAlerts, scopeNormal},
+	{"faster", &keyFaster, scopeNormal},
+	{"slower", &keySlower, scopeNormal},
+	{"pause", &keyPause, scopeNormal},
+	{"step", &keyStep, scopeNormal},
+	{"renice", &keyRenice, scopeNormal},
+	{"suspend", &keySuspend, scopeNormal},
+	{"resume", &keyResume, scopeNormal},
+	{"column_up", &keyMoveUp, scopeColumns},
+	{"column_down", &keyMoveDown, scopeColumns},
+	{"column_sort", &keySortBy, scopeColumns},
+	{"filter_mode", &keyFilterMode, scopeInput},
+	{"filter_target", &keyFilterTarget, scopeInput},
+}
+
+// keyScopes names each mode for collision errors.
+var keyScopes = []struct {
+	scope keyScope
+	where string
+}{
+	{scopeNormal, ""},
+	{scopeColumns, " in the column chooser"},
+	{scopeSignal, " in the signal picker"},
+	{scopeInput, " in the filter prompt"},
+}
+
+// reservedKeys are the fixed keys a mode handles besides its remappable
+// ones, with what they do.
+func reservedKeys(scope keyScope) map[string]string {
+	owner := map[string]string{"ctrl+c": "force quit"}
+	switch scope {
+	case scopeSignal:
+		for d := '1'; d <= '9'; d++ {
+			owner[string(d)] = "signal " + string(d)
+		}
+	case scopeInput:
+		km := textinput.DefaultKeyMap
+		for _, b := range []key.Binding{
+			km.CharacterForward, km.CharacterBackward, km.WordForward, km.WordBackward,
+			km.DeleteWordBackward, km.DeleteWordForward, km.DeleteAfterCursor, km.DeleteBeforeCursor,
+			km.DeleteCharacterBackward, km.DeleteCharacterForward, km.LineStart, km.LineEnd, km.Paste,
+		} {
+			for _, k := range b.Keys() {
+				owner[k] = "text editing"
+			}
+		}
+	}
+	return owner
+}
+
+// remapKeys applies name → key overrides and rejects unknown names and
+// keys that collide within a mode. Keys use Bubble Tea's names ("ctrl+x",
+// "up", "delete"); "space" is accepted for the space bar.
+func remapKeys(overrides map[string]string) error {
+	byName := make(map[string]*string, len(keyBindings))
+	for _, b := range keyBindings {
+		byName[b.name] = b.key
+	}
+	for _, name := range sortedKeys(overrides) {
+		key := overrides[name]
+		ptr, ok := byName[name]
+		if !ok {
+			return fmt.Errorf("keys: unknown action %q", name)
+		}
+		if key == "" {
+			return fmt.Errorf("keys: %s is empty", name)
+		}
+		if key == "space" {
+			key = " "
+		}
+		*ptr = key
+	}
+
+	for _, s := range keyScopes {
+		owner := reservedKeys(s.scope)
+		for _, b := range keyBindings {
+			if b.scope&s.scope == 0 {
+				continue
+			}
+			k := *b.key
+			if r, size := utf8.DecodeRuneInString(k); s.scope == scopeInput && size == len(k) && unicode.IsPrint(r) {
+				return fmt.Errorf("keys: %q is bound to %s, so it couldn't be typed%s", keyLabel(k), b.name, s.where)
+			}
+			if prev, ok := owner[k]; ok {
+				return fmt.Errorf("keys: %q is bound to both %s and %s%s", keyLabel(k), prev, b.name, s.where)
+			}
+			owner[k] = b.name
+		}
+	}
+	helpText = footerHelp()
+	return nil
+}
+
+// keyLabel is how a key is shown in the footer and help screen.
+func keyLabel(k string) string {
+	switch k {
+	case " ":
+		return "Space"
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	case "delete":
+		return "Del"
+	case "tab", "esc", "enter":
+		return strings.ToUpper(k[:1]) + k[1:]
+	}
+	if strings.HasPrefix(k, "ctrl+") {
+		return "Ctrl+" + strings.ToUpper(k[5:])
+	}
+	return k
+}
+
 // helpText is rendered in the footer status bar during Normal mode.
-const helpText = "q quit  / filter  Tab sort  Del/K kill  j↓  k↑  1=PID 2=Name 3=CPU 4=Mem 5=Thrd 6=User  ? help"
+var helpText = footerHelp()
+
+func footerHelp() string {
+	l := keyLabel
+	return fmt.Sprintf("%s quit  %s filter  %s sort  %s mark  %s/%s signal  %s nice  %s↓  %s↑  "+
+		"%s=PID %s=Name %s=CPU %s=Mem %s=Thrd %s=User  %s info  %s tree  %s columns  %s pause  %s help",
+		l(keyQuit), l(keyFilter), l(keyTab), l(keyMark), l(keyDel), l(keyKill), l(keyRenice), l(keyVimDown), l(keyVimUp),
+		l(keySortPID), l(keySortName), l(keySortCPU), l(keySortMem), l(keySortStatus), l(keySortUser),
+		l(keyEnter), l(keyTree), l(keyColumns), l(keyPause), l(keyHelp))
+}
+
+// keysLabel lists keys that do the same thing, e.g. "Esc / q", for the
+// footers of the full-screen views.
+func keysLabel(keys ...string) string {
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		labels[i] = keyLabel(k)
+	}
+	return strings.Join(labels, " / ")
+}
+
+// scrollHelp is the footer hint of the scrolling views.
+func scrollHelp() string {
+	return keyLabel(keyVimDown) + "/" + keyLabel(keyVimUp) + " scroll"
+}
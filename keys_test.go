@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// saveKeys returns a function restoring every remappable key, for tests
+// that change them.
+func saveKeys() func() {
+	saved := make([]string, len(keyBindings))
+	for i, b := range keyBindings {
+		saved[i] = *b.key
+	}
+	return func() {
+		for i, b := range keyBindings {
+			*b.key = saved[i]
+		}
+		helpText = footerHelp()
+	}
+}
+
+func TestFootersFollowRemappedKeys(t *testing.T) {
+	defer saveKeys()()
+	keyVimDown, keyVimUp, keyQuit, keyMoveDown = "ctrl+n", "ctrl+p", "Q", "ctrl+j"
+
+	footer := func(m Model) string {
+		lines := strings.Split(m.View(), "\n")
+		return lines[len(lines)-1]
+	}
+	m := goldenModel(t, 100, 30)
+	if got := footer(send(m, keyMsg(keyFiles))); !strings.Contains(got, "Ctrl+N/Ctrl+P scroll  ·  Esc / Q back") {
+		t.Errorf("open-files footer = %q, want the remapped keys", got)
+	}
+	if got := footer(send(m, keyMsg(keyColumns))); !strings.Contains(got, "Ctrl+J/K move down/up") {
+		t.Errorf("column chooser footer = %q, want the remapped keys", got)
+	}
+}
+
+func TestRemapKeys(t *testing.T) {
+	defer saveKeys()()
+	if err := remapKeys(map[string]string{"tree": "T", "mark": "space"}); err != nil {
+		t.Fatalf("remapping tree and mark: %v", err)
+	}
+	if keyTree != "T" || keyMark != " " {
+		t.Errorf("tree = %q, mark = %q after remapping", keyTree, keyMark)
+	}
+
+	err := remapKeys(map[string]string{"tree": keyQuit})
+	if err == nil || !strings.Contains(err.Error(), "bound to both") {
+		t.Errorf("binding tree to the quit key: err = %v, want a collision", err)
+	}
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// treeKillGrace is how long a tree kill waits for signalled processes to
+// exit before reporting the ones still running as survivors.
+const treeKillGrace = time.Second
+
+// terminatingSignals are the picker entries whose default action ends the
+// process; only for these does a tree kill check for survivors.
+var terminatingSignals = map[string]bool{
+	"SIGTERM": true,
+	"SIGINT":  true,
+	"SIGHUP":  true,
+	"SIGQUIT": true,
+	"SIGKILL": true,
+}
+
+// treeTargetsMsg carries the processes of one or more trees, leaves first,
+// and each one's depth below its root.
+type treeTargetsMsg struct {
+	Targets []ProcessRow
+	Depth   map[int32]int
+	Err     error
+}
+
+// openKillTree opens the signal picker for the whole tree under each action
+// target. The descendants are listed asynchronously; until they arrive the
+// picker shows the roots and refuses to send.
+func (m *Model) openKillTree() tea.Cmd {
+	targets := m.actionTargets()
+	if len(targets) == 0 {
+		return nil
+	}
+	m.killTargets = targets
+	m.killTree = true
+	m.killDepth = nil
+	m.sigCursor = m.lastSig
+	m.mode = ModeSignal
+	roots := make([]int32, len(targets))
+	for i, t := range targets {
+		roots[i] = t.PID
+	}
+	return collectTree(roots)
+}
+
+// collectTree walks every root's descendants with gopsutil's Children.
+// The result is in post-order — each process after all of its descendants —
+// so signalling in order never orphans a child before it has been reached.
+func collectTree(roots []int32) tea.Cmd {
+	return func() tea.Msg {
+		msg := treeTargetsMsg{Depth: map[int32]int{}}
+		var walk func(p *process.Process, depth int) error
+		walk = func(p *process.Process, depth int) error {
+			if _, seen := msg.Depth[p.Pid]; seen {
+				return nil
+			}
+			msg.Depth[p.Pid] = depth
+			children, err := childrenOf(p)
+			if err != nil {
+				return fmt.Errorf("listing children of PID %d: %v", p.Pid, err)
+			}
+			for _, c := range children {
+				if err := walk(c, depth+1); err != nil {
+					return err
+				}
+			}
+			msg.Targets = append(msg.Targets, describeProcess(p))
+			return nil
+		}
+		for _, pid := range roots {
+			p, err := process.NewProcess(pid)
+			if err != nil {
+				continue // exited since it was selected
+			}
+			if err := walk(p, 0); err != nil {
+				return treeTargetsMsg{Err: err}
+			}
+		}
+		if len(msg.Targets) == 0 {
+			msg.Err = errors.New("process has exited")
+		}
+		return msg
+	}
+}
+
+// childrenOf wraps Process.Children. On Linux and macOS gopsutil runs
+// `pgrep -P`, which exits 1 for a process with no children; that and
+// ErrorNoChildren both just mean a leaf. A child exiting mid-lookup makes
+// the whole call fail, so it is retried a couple of times.
+func childrenOf(p *process.Process) ([]*process.Process, error) {
+	var err error
+	for attempt := 0; attempt < 3; attempt++ {
+		var children []*process.Process
+		children, err = p.Children()
+		var exitErr *exec.ExitError
+		switch {
+		case err == nil:
+			return children, nil
+		case errors.Is(err, process.ErrorNoChildren),
+			errors.As(err, &exitErr) && exitErr.ExitCode() == 1:
+			return nil, nil
+		case !errors.Is(err, process.ErrorProcessNotRunning):
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// describeProcess fills the ProcessRow fields the picker shows.
+func describeProcess(p *process.Process) ProcessRow {
+	r := ProcessRow{PID: p.Pid}
+	r.Name, _ = p.Name()
+	r.User, _ = p.Username()
+	return r
+}
+
+// killTree signals targets in order (leaves first). For terminating signals
+// it then waits up to treeKillGrace and marks the processes still running.
+func killTree(targets []ProcessRow, sig signalOption) tea.Cmd {
+	targets = append([]ProcessRow(nil), targets...)
+	return func() tea.Msg {
+		results := make([]killResultMsg, 0, len(targets))
+		for _, t := range targets {
+			p, err := process.NewProcess(t.PID)
+			if err == nil {
+				err = sendSignal(p, sig.Sig)
+			}
+			results = append(results, killResultMsg{PID: t.PID, Name: t.Name, Signal: sig.Name, Err: err})
+		}
+		if terminatingSignals[sig.Name] {
+			markSurvivors(results)
+		}
+		return killBatchResultMsg{Signal: sig.Name, Results: results, Tree: true}
+	}
+}
+
+// markSurvivors polls the successfully signalled PIDs until they are all
+// gone or treeKillGrace has passed, and flags the rest as survivors.
+// Zombies count as gone: they have exited and only await reaping.
+func markSurvivors(results []killResultMsg) {
+	deadline := time.Now().Add(treeKillGrace)
+	for {
+		alive := 0
+		for i := range results {
+			r := &results[i]
+			r.Survived = r.Err == nil && processAlive(r.PID)
+			if r.Survived {
+				alive++
+			}
+		}
+		if alive == 0 || time.Now().After(deadline) {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func processAlive(pid int32) bool {
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return false
+	}
+	status, err := p.Status()
+	if err != nil {
+		return true
+	}
+	for _, s := range status {
+		if s == process.Zombie {
+			return false
+		}
+	}
+	return true
+}
+
+// handleTreeTargets installs the collected tree as the picker's targets.
+func (m *Model) handleTreeTargets(msg treeTargetsMsg) {
+	if m.mode != ModeSignal || !m.killTree {
+		return // picker closed while the tree was being listed
+	}
+	if msg.Err != nil {
+		m.mode = ModeNormal
+		m.killTargets, m.killTree = nil, false
+		m.statusMsg = "kill tree: " + msg.Err.Error()
+		return
+	}
+	m.killTargets = msg.Targets
+	m.killDepth = msg.Depth
+}
+
+// renderTreeTargets describes a tree kill for the signal picker: a count
+// and the processes indented under their parents, up to max lines.
+func (m *Model) renderTreeTargets(max int) string {
+	if m.killDepth == nil {
+		var b strings.Builder
+		for _, t := range m.killTargets {
+			b.WriteString(fmt.Sprintf("  PID %d (%s)\n", t.PID, t.Name))
+		}
+		return b.String() + "  " + styleOverlayHint.Render("collecting descendants…")
+	}
+
+	roots := 0
+	for _, d := range m.killDepth {
+		if d == 0 {
+			roots++
+		}
+	}
+	n := len(m.killTargets)
+	var head string
+	if roots == 1 {
+		root := m.killTargets[n-1]
+		head = fmt.Sprintf("  PID %d (%s) and %d descendants — %d processes:\n",
+			root.PID, root.Name, n-1, n)
+	} else {
+		head = fmt.Sprintf("  %d trees, %d processes:\n", roots, n)
+	}
+
+	// killTargets is leaves-first; reversed, every parent precedes its
+	// subtree, which reads like the tree view.
+	var b strings.Builder
+	for i := n - 1; i >= 0; i-- {
+		if n-1-i == max {
+			b.WriteString(styleOverlayHint.Render(fmt.Sprintf("    …and %d more", i+1)))
+			b.WriteString("\n")
+			break
+		}
+		t := m.killTargets[i]
+		indent := strings.Repeat("  ", m.killDepth[t.PID])
+		b.WriteString(fmt.Sprintf("    %7d  %s%s  (%s)\n", t.PID, indent, truncate(t.Name, 24), t.User))
+	}
+	return head + b.String() + "  " + styleOverlayHint.Render("signalled leaves first")
+}
+
+// summarizeTreeKill condenses a tree kill into one status-bar line.
+func summarizeTreeKill(msg killBatchResultMsg) string {
+	failed, survived := 0, 0
+	for _, r := range msg.Results {
+		switch {
+		case r.Err != nil:
+			failed++
+		case r.Survived:
+			survived++
+		}
+	}
+	if !terminatingSignals[msg.Signal] {
+		return fmt.Sprintf("sent %s to %d processes in tree: %d ok, %d failed",
+			msg.Signal, len(msg.Results), len(msg.Results)-failed, failed)
+	}
+	return fmt.Sprintf("sent %s to %d processes in tree: %d exited, %d survived, %d failed",
+		msg.Signal, len(msg.Results), len(msg.Results)-failed-survived, survived, failed)
+}
@@ -9,17 +9,92 @@ import (
 )
 
 func main() {
-	noColor    := flag.Bool("no-color", false, "disable ANSI colour output")
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		if err := runDiff(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "gomon diff: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	cfgPath := flag.String("config", "", "config file (default $XDG_CONFIG_HOME/gomon/config)")
+	noColor := flag.Bool("no-color", false, "disable ANSI colour output")
 	screenshot := flag.Bool("screenshot", false, "render one frame to stdout and exit")
-	scHelp     := flag.Bool("screenshot-help", false, "render the help screen to stdout and exit")
-	scWidth    := flag.Int("w", 120, "terminal width for --screenshot")
-	scHeight   := flag.Int("h", 35, "terminal height for --screenshot")
+	scHelp := flag.Bool("screenshot-help", false, "render the help screen to stdout and exit")
+	scWidth := flag.Int("w", 120, "terminal width for --screenshot")
+	scHeight := flag.Int("h", 35, "terminal height for --screenshot")
+	batch := flag.Bool("batch", false, "write snapshots to stdout instead of running the TUI")
+	batchFmt := flag.String("format", "text", "--batch output format: json (NDJSON), csv or text")
+	batchN := flag.Int("n", 0, "--batch iterations (0 = until interrupted)")
+	batchEvery := flag.Duration("interval", tickInterval, "--batch sampling interval")
+	batchLimit := flag.Int("limit", 0, "--batch max rows per snapshot (0 = all)")
+	sortBy := flag.String("sort", "cpu", "--batch sort column (any column key, e.g. pid, name, cpu, mem, threads, user)")
+	reverse := flag.Bool("reverse", false, "--batch reverse the default sort direction")
+	filter := flag.String("filter", "", "--batch process filter")
+	serve := flag.String("serve", "", "serve Prometheus metrics on this address (e.g. :9100) instead of running the TUI")
+	topN := flag.Int("top", 50, "--serve export only the N busiest process series (0 = all)")
+	allow := flag.String("allow", "", "--serve comma-separated process names to export (default all)")
+	aggregate := flag.String("aggregate", "pid", "--serve process series key: pid, name or user")
+	record := flag.String("record", "", "append every sample the TUI takes to this file (compressed)")
+	recordAll := flag.Bool("record-all", false, "--record every process field, so a replay can show any column (larger, costlier)")
+	replay := flag.String("replay", "", "play back a file written by --record instead of the live system")
+	flag.Float64Var(&availWarnPct, "avail-warn", availWarnPct, "highlight memory when available RAM falls below this percent")
+	flag.Float64Var(&swapWarnPct, "swap-warn", swapWarnPct, "highlight swap when usage rises above this percent")
 	flag.Parse()
 
+	// Explicit flags win over the config file.
+	flagSet := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { flagSet[f.Name] = true })
+	if err := loadConfig(*cfgPath, flagSet); err != nil {
+		fmt.Fprintf(os.Stderr, "gomon: %v\n", err)
+		os.Exit(1)
+	}
+	if !flagSet["interval"] {
+		*batchEvery = tickInterval
+	}
+	if !flagSet["sort"] {
+		*sortBy = columnDef(defaultSort).key
+	}
+	if !flagSet["reverse"] {
+		*reverse = defaultSortAsc != defaultSort.defaultAsc()
+	}
+	if !flagSet["filter"] {
+		*filter = defaultFilter
+	}
+
 	if *noColor {
 		os.Setenv("NO_COLOR", "1")
 	}
 
+	if *serve != "" {
+		err := runMetricsServer(metricsOptions{
+			Addr:      *serve,
+			TopN:      *topN,
+			Allow:     parseAllowList(*allow),
+			Aggregate: *aggregate,
+			Interval:  tickInterval,
+		})
+		fmt.Fprintf(os.Stderr, "gomon: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *batch {
+		err := runBatch(batchOptions{
+			Format:     *batchFmt,
+			Iterations: *batchN,
+			Interval:   *batchEvery,
+			Limit:      *batchLimit,
+			Sort:       *sortBy,
+			Reverse:    *reverse,
+			Filter:     *filter,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gomon: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if *scHelp {
 		runScreenshot(*scWidth, *scHeight, ModeHelp)
 		return
@@ -29,13 +104,42 @@ func main() {
 		return
 	}
 
-	m := NewModel()
+	if *record != "" && *replay != "" {
+		fmt.Fprintln(os.Stderr, "gomon: --record and --replay can't be combined")
+		os.Exit(2)
+	}
+	var c Collector = liveCollector{}
+	if *replay != "" {
+		rc, err := loadRecording(*replay)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gomon: %v\n", err)
+			os.Exit(1)
+		}
+		c = rc
+	}
+	var rec *recorder
+	if *record != "" {
+		var err error
+		if rec, err = openRecorder(*record); err != nil {
+			fmt.Fprintf(os.Stderr, "gomon: %v\n", err)
+			os.Exit(1)
+		}
+		c = recordingCollector{Collector: c, rec: rec, all: *recordAll}
+	}
+
+	m := NewModel(c)
 	p := tea.NewProgram(
 		m,
 		tea.WithAltScreen(),
 	)
 
-	if _, err := p.Run(); err != nil {
+	_, err := p.Run()
+	if rec != nil {
+		if cerr := rec.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("recording %s: %v", *record, cerr)
+		}
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "gomon: %v\n", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,342 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// matchMode selects how bare words in the filter are matched.
+type matchMode int
+
+const (
+	matchSubstring matchMode = iota
+	matchRegex
+	matchFuzzy
+)
+
+func (mm matchMode) String() string {
+	switch mm {
+	case matchRegex:
+		return "regex"
+	case matchFuzzy:
+		return "fuzzy"
+	default:
+		return "substr"
+	}
+}
+
+// next cycles substring → regex → fuzzy → substring.
+func (mm matchMode) next() matchMode {
+	return (mm + 1) % 3
+}
+
+// matchOptions controls how parseQuery treats bare words.
+type matchOptions struct {
+	mode    matchMode
+	cmdline bool // match the full command line instead of the name
+}
+
+// patternTerm is a bare word, matched against the name or command line
+// using the active matchMode. Unlike field terms it can report which
+// characters matched and, in fuzzy mode, how well.
+type patternTerm struct {
+	get  func(r *ProcessRow) string
+	mode matchMode
+	pat  []rune // lower-cased
+	re   *regexp.Regexp
+}
+
+func newPatternTerm(w string, opts matchOptions) (patternTerm, error) {
+	t := patternTerm{get: queryFields["name"].text, mode: opts.mode, pat: lowerRunes(w)}
+	if opts.cmdline {
+		t.get = queryFields["cmd"].text
+	}
+	if opts.mode == matchRegex {
+		re, err := regexp.Compile("(?i)" + w)
+		if err != nil {
+			return t, fmt.Errorf("bad regex: %v", err)
+		}
+		t.re = re
+	}
+	return t, nil
+}
+
+func (t patternTerm) match(r *ProcessRow) bool {
+	switch t.mode {
+	case matchRegex:
+		return t.re.MatchString(t.get(r))
+	case matchFuzzy:
+		_, _, ok := fuzzyMatch(t.pat, []rune(t.get(r)))
+		return ok
+	default:
+		return substringIndex(lowerRunes(t.get(r)), t.pat) >= 0
+	}
+}
+
+// positions returns the rune indices of the matched characters, or nil.
+func (t patternTerm) positions(r *ProcessRow) []int {
+	s := t.get(r)
+	switch t.mode {
+	case matchRegex:
+		loc := t.re.FindStringIndex(s)
+		if loc == nil || loc[0] == loc[1] {
+			return nil
+		}
+		start := len([]rune(s[:loc[0]]))
+		return runeSpan(start, len([]rune(s[loc[0]:loc[1]])))
+	case matchFuzzy:
+		_, pos, _ := fuzzyMatch(t.pat, []rune(s))
+		return pos
+	default:
+		i := substringIndex(lowerRunes(s), t.pat)
+		if i < 0 {
+			return nil
+		}
+		return runeSpan(i, len(t.pat))
+	}
+}
+
+// score is the fuzzy match quality (higher is better); 0 for other modes.
+func (t patternTerm) score(r *ProcessRow) int {
+	if t.mode != matchFuzzy {
+		return 0
+	}
+	s, _, _ := fuzzyMatch(t.pat, []rune(t.get(r)))
+	return s
+}
+
+// Score sums the fuzzy scores of the bare words r matches.
+func (q *query) Score(r *ProcessRow) int {
+	if q == nil {
+		return 0
+	}
+	total := 0
+	for _, p := range q.patterns {
+		if p.match(r) {
+			total += p.score(r)
+		}
+	}
+	return total
+}
+
+// Highlights returns the sorted rune indices, in the matched text, of every
+// character a bare word matched.
+func (q *query) Highlights(r *ProcessRow) []int {
+	if q == nil || len(q.patterns) == 0 {
+		return nil
+	}
+	seen := map[int]bool{}
+	var out []int
+	for _, p := range q.patterns {
+		for _, i := range p.positions(r) {
+			if !seen[i] {
+				seen[i] = true
+				out = append(out, i)
+			}
+		}
+	}
+	sort.Ints(out)
+	return out
+}
+
+// Fuzzy scoring, loosely after fzf's v1 algorithm: every matched character
+// scores, gaps cost, and matches that are consecutive or start a word
+// (after a separator, a digit run or a camelCase hump) earn a bonus.
+const (
+	fuzzyScoreMatch       = 16
+	fuzzyGapStart         = -3
+	fuzzyGapExtend        = -1
+	fuzzyBonusBoundary    = 8
+	fuzzyBonusConsecutive = 4
+	fuzzyBonusFirstChar   = 2 // multiplier for a boundary bonus on the first character
+)
+
+// fuzzyMatch reports whether pat (lower-cased) is a subsequence of text and,
+// if so, its score and the matched rune positions. It finds the first
+// occurrence of the subsequence, then scans back from its end to tighten
+// the window so "gc" in "go-cache" prefers the later, shorter match.
+func fuzzyMatch(pat, text []rune) (int, []int, bool) {
+	if len(pat) == 0 {
+		return 0, nil, true
+	}
+	lower := make([]rune, len(text))
+	for i, c := range text {
+		lower[i] = unicode.ToLower(c)
+	}
+
+	pi, end := 0, -1
+	for ti, c := range lower {
+		if c == pat[pi] {
+			pi++
+			if pi == len(pat) {
+				end = ti + 1
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return 0, nil, false
+	}
+	start := 0
+	pi = len(pat) - 1
+	for ti := end - 1; ti >= 0; ti-- {
+		if lower[ti] == pat[pi] {
+			pi--
+			if pi < 0 {
+				start = ti
+				break
+			}
+		}
+	}
+
+	score := 0
+	pos := make([]int, 0, len(pat))
+	pi = 0
+	inGap := false
+	for ti := start; ti < end && pi < len(pat); ti++ {
+		if lower[ti] != pat[pi] {
+			if inGap {
+				score += fuzzyGapExtend
+			} else {
+				score += fuzzyGapStart
+				inGap = true
+			}
+			continue
+		}
+		score += fuzzyScoreMatch
+		bonus := 0
+		if isWordStart(text, ti) {
+			bonus = fuzzyBonusBoundary
+		}
+		if len(pos) > 0 && pos[len(pos)-1] == ti-1 {
+			bonus += fuzzyBonusConsecutive
+		}
+		if pi == 0 {
+			bonus *= fuzzyBonusFirstChar
+		}
+		score += bonus
+		pos = append(pos, ti)
+		inGap = false
+		pi++
+	}
+	return score, pos, true
+}
+
+// isWordStart reports whether text[i] begins a word.
+func isWordStart(text []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev, c := text[i-1], text[i]
+	switch {
+	case !unicode.IsLetter(prev) && !unicode.IsDigit(prev):
+		return unicode.IsLetter(c) || unicode.IsDigit(c)
+	case unicode.IsLower(prev) && unicode.IsUpper(c):
+		return true
+	case unicode.IsLetter(prev) && unicode.IsDigit(c):
+		return true
+	}
+	return false
+}
+
+// substringIndex returns the rune index of sub in s, or -1.
+func substringIndex(s, sub []rune) int {
+	for i := 0; i+len(sub) <= len(s); i++ {
+		j := 0
+		for j < len(sub) && s[i+j] == sub[j] {
+			j++
+		}
+		if j == len(sub) {
+			return i
+		}
+	}
+	return -1
+}
+
+func lowerRunes(s string) []rune {
+	rs := []rune(s)
+	for i, c := range rs {
+		rs[i] = unicode.ToLower(c)
+	}
+	return rs
+}
+
+func runeSpan(start, n int) []int {
+	out := make([]int, n)
+	for i := range out {
+		out[i] = start + i
+	}
+	return out
+}
+
+// cmdNamePositions maps highlight positions in a command line onto the
+// process name, which is usually the basename of the command's first word
+// (possibly cut short, as Linux does at 15 characters). Positions outside
+// that basename, or in a command line the name doesn't come from, are
+// dropped.
+func cmdNamePositions(positions []int, cmdline, name string) []int {
+	rs := []rune(cmdline)
+	end := 0
+	for end < len(rs) && rs[end] != ' ' {
+		end++
+	}
+	start := end
+	for start > 0 && rs[start-1] != '/' && rs[start-1] != '\\' {
+		start--
+	}
+	nr := []rune(name)
+	if len(nr) == 0 || len(nr) > end-start || string(rs[start:start+len(nr)]) != name {
+		return nil
+	}
+	var out []int
+	for _, p := range positions {
+		if p >= start && p < start+len(nr) {
+			out = append(out, p-start)
+		}
+	}
+	return out
+}
+
+// highlightRunes styles the runes of cell at positions (offset by offset,
+// e.g. a tree prefix) with styleFilterMatch and the rest with base. cell is
+// already truncated and padded, so positions past the end are ignored; so
+// is the "..." that truncate leaves in place of trailing characters.
+func highlightRunes(cell string, offset int, positions []int, original string, base lipgloss.Style) string {
+	if len(positions) == 0 {
+		return base.Render(cell)
+	}
+	rs := []rune(cell)
+	orig := []rune(original)
+	hit := make([]bool, len(rs))
+	for _, p := range positions {
+		i := p + offset
+		if i < len(rs) && p < len(orig) && rs[i] == orig[p] {
+			hit[i] = true
+		}
+	}
+	if strings.HasSuffix(strings.TrimRight(cell, " "), "...") && len(orig)+offset > len(rs) {
+		for i := len(rs) - 3; i >= 0 && i < len(rs); i++ {
+			hit[i] = false
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(rs); {
+		j := i
+		for j < len(rs) && hit[j] == hit[i] {
+			j++
+		}
+		if hit[i] {
+			b.WriteString(styleFilterMatch.Inherit(base).Render(string(rs[i:j])))
+		} else {
+			b.WriteString(base.Render(string(rs[i:j])))
+		}
+		i = j
+	}
+	return b.String()
+}
@@ -0,0 +1,14 @@
+//go:build linux
+
+package main
+
+import "github.com/shirou/gopsutil/v3/process"
+
+// sharedMemBytes returns the process's shared (file-backed) resident memory.
+func sharedMemBytes(p *process.Process) (uint64, error) {
+	ex, err := p.MemoryInfoEx()
+	if err != nil {
+		return 0, err
+	}
+	return ex.Shared, nil
+}
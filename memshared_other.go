@@ -0,0 +1,15 @@
+//go:build !linux
+
+package main
+
+import (
+	"errors"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// sharedMemBytes is only implemented on Linux; elsewhere gopsutil's
+// MemoryInfoEx carries no shared-memory figure.
+func sharedMemBytes(p *process.Process) (uint64, error) {
+	return 0, errors.New("shared memory not available on this platform")
+}
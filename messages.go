@@ -1,6 +1,9 @@
 package main
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // ---------------------------------------------------------------------------
 // App modes
@@ -9,60 +12,168 @@ import "time"
 type AppMode int
 
 const (
-	ModeNormal      AppMode = iota
+	ModeNormal AppMode = iota
 	ModeFilter
-	ModeConfirmKill
+	ModeSignal
+	ModeSignalResult
 	ModeHelp
+	ModeDetail
+	ModeColumns
+	ModeRenice
+	ModeConnections
+	ModeFiles
+	ModeDiff
+	ModeAlerts
 )
 
 // ---------------------------------------------------------------------------
 // Sort columns
 // ---------------------------------------------------------------------------
 
+// SortColumn identifies a process-table column; every column is sortable.
+// See columnRegistry in columns.go for widths, formatters and comparators.
 type SortColumn int
 
 const (
-	SortPID    SortColumn = iota // 1
-	SortName                     // 2
-	SortCPU                      // 3
-	SortMem                      // 4
-	SortThreads                  // 5
-	SortUser                     // 6
+	SortPID     SortColumn = iota // 1
+	SortName                      // 2
+	SortCPU                       // 3
+	SortMem                       // 4
+	SortThreads                   // 5
+	SortUser                      // 6
+	SortPPID
+	SortCmdline
+	SortState
+	SortNice
+	SortPriority
+	SortVSZ
+	SortShared
+	SortCPUTime
+	SortStart
+	SortElapsed
+	SortFDs
+	SortRead
+	SortWrite
+	SortGroup
+	SortTTY
+	SortCPUHist
+	SortMemHist
 )
 
+// defaultAsc reports the natural direction for a column: text and PID sort
+// ascending, numeric load columns descending (highest first).
+func (c SortColumn) defaultAsc() bool {
+	return columnDef(c).asc
+}
+
 // ---------------------------------------------------------------------------
 // Process row
 // ---------------------------------------------------------------------------
 
 type ProcessRow struct {
-	PID     int32
-	Name    string
-	CPU     float64 // percent (0–100*numCPU)
-	MemMB   float64
-	Threads int32
-	User    string
+	PID     int32   `json:"pid"`
+	PPID    int32   `json:"ppid"`
+	Name    string  `json:"name"`
+	CPU     float64 `json:"cpu"` // percent (0–100*numCPU)
+	MemMB   float64 `json:"mem_mb"`
+	VSZMB   float64 `json:"vsz_mb"`
+	Threads int32   `json:"threads"`
+	User    string  `json:"user"`
+
+	// Optional fields — only populated when a visible column needs them
+	// (see procField).
+	Cmdline  string    `json:"cmdline,omitempty"`
+	State    string    `json:"state,omitempty"` // ps-style letter: R, S, D, Z, T, I …
+	Nice     int32     `json:"nice,omitempty"`
+	Priority int32     `json:"priority,omitempty"`
+	SharedMB float64   `json:"shared_mb,omitempty"`
+	CPUTime  float64   `json:"cpu_time,omitempty"` // user+system seconds
+	Started  time.Time `json:"started,omitempty"`  // see MarshalJSON
+	FDs      int32     `json:"fds,omitempty"`
+	FDLimit  uint64    `json:"fd_limit,omitempty"` // RLIMIT_NOFILE soft limit; 0 if unknown
+	ReadBps  float64   `json:"read_bps,omitempty"`
+	WriteBps float64   `json:"write_bps,omitempty"`
+	Group    string    `json:"group,omitempty"`
+	TTY      string    `json:"tty,omitempty"`
+	Ports    []uint32  `json:"ports,omitempty"` // listening TCP / bound UDP ports
+}
+
+// MarshalJSON leaves out a zero Started, which omitempty can't do for a
+// struct, so rows whose start time wasn't collected have no started key.
+func (r ProcessRow) MarshalJSON() ([]byte, error) {
+	type row ProcessRow // without the MarshalJSON method
+	var started *time.Time
+	if !r.Started.IsZero() {
+		started = &r.Started
+	}
+	return json.Marshal(struct {
+		row
+		Started *time.Time `json:"started,omitempty"`
+	}{row(r), started})
 }
 
 // ---------------------------------------------------------------------------
 // Tea messages
 // ---------------------------------------------------------------------------
 
-type tickMsg time.Time
+// tickMsg fires every refresh interval. gen identifies the tick chain it
+// belongs to, so ticks scheduled before a rate change or pause are dropped.
+type tickMsg struct {
+	at  time.Time
+	gen int
+}
 
 type sysStatsMsg struct {
-	Hostname string
-	Uptime   string
-	MemUsed  float64 // GB
-	MemTotal float64 // GB
-	Err      error
+	Hostname     string
+	Uptime       string
+	UptimeSecs   uint64
+	MemUsed      float64 // GB
+	MemTotal     float64 // GB
+	MemAvail     float64 // GB — reclaimable without swapping
+	MemBuffers   float64 // GB
+	MemCached    float64 // GB
+	MemDirty     float64 // GB — pages waiting to be written back
+	MemWriteback float64 // GB — pages being written back now
+	SwapUsed     float64 // GB
+	SwapTotal    float64 // GB
+
+	CPUTotal   float64   // percent across all cores
+	CPUPerCore []float64 // percent per logical core
+	Load1      float64
+	Load5      float64
+	Load15     float64
+	HasLoad    bool // load averages unavailable on some platforms
+	TasksRun   int  // runnable tasks (0 if unknown)
+	TasksTotal int
+
+	Err error `json:"-"`
+	gen int   // fetchGen when the sample was requested
 }
 
 type processesMsg struct {
 	Procs []ProcessRow
 	Err   error
+	gen   int // fetchGen when the sample was requested
+}
+
+type processDetailMsg struct {
+	PID    int32
+	Detail *ProcessDetail
+	Err    error
 }
 
 type killResultMsg struct {
-	PID int32
-	Err error
+	PID      int32
+	Name     string // set by tree kills, whose targets may vanish from allProcs
+	Signal   string // e.g. "SIGTERM"
+	Err      error
+	Survived bool // tree kill: still running after treeKillGrace
+}
+
+// killBatchResultMsg reports one signal sent to every marked process, or
+// to every process of a tree.
+type killBatchResultMsg struct {
+	Signal  string
+	Results []killResultMsg
+	Tree    bool // leaves-first tree kill; see killtree.go
 }
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// metricsOptions controls the /metrics exporter and its label cardinality.
+type metricsOptions struct {
+	Addr      string
+	TopN      int             // keep only the N busiest series (0 = all)
+	Allow     map[string]bool // process names to export (empty = all)
+	Aggregate string          // "pid", "name" or "user"
+	Interval  time.Duration
+}
+
+// metricsSeries is one exported process series — a single PID, or the sum
+// of every process sharing a name or user when aggregating.
+type metricsSeries struct {
+	labels  [][2]string
+	cpu     float64
+	rssB    float64
+	threads float64
+	count   int
+}
+
+// metricsExporter samples in the background so CPU% is a real delta over
+// the sampling interval, independent of how often Prometheus scrapes.
+// Until the first sample has been taken, scrapes get 503 rather than a set
+// of zero gauges a scraper would store as real data.
+type metricsExporter struct {
+	opts metricsOptions
+
+	mu      sync.RWMutex
+	stats   sysStatsMsg
+	procs   []ProcessRow
+	err     error
+	sampled bool
+}
+
+// runMetricsServer serves Prometheus text-format metrics until it fails.
+func runMetricsServer(opts metricsOptions) error {
+	switch opts.Aggregate {
+	case "pid", "name", "user":
+	default:
+		return fmt.Errorf("unknown aggregation %q (want pid, name or user)", opts.Aggregate)
+	}
+
+	e := &metricsExporter{opts: opts}
+	CollectSysStats() // seed CPU baselines
+	CollectProcesses(fieldNone)
+	go e.sampleLoop()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", e.serveMetrics)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintln(w, `<html><body><a href="/metrics">/metrics</a></body></html>`)
+	})
+
+	fmt.Fprintf(os.Stderr, "gomon: serving metrics on %s/metrics\n", opts.Addr)
+	return http.ListenAndServe(opts.Addr, mux)
+}
+
+func (e *metricsExporter) sampleLoop() {
+	for {
+		time.Sleep(e.opts.Interval)
+		procs := CollectProcesses(fieldNone)
+		stats := CollectSysStats()
+		e.store(stats, procs)
+	}
+}
+
+func (e *metricsExporter) store(stats sysStatsMsg, procs processesMsg) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.stats = stats
+	e.procs = procs.Procs
+	e.err = procs.Err
+	if e.err == nil {
+		e.err = stats.Err
+	}
+	e.sampled = true
+}
+
+func (e *metricsExporter) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	e.mu.RLock()
+	stats, procs, err, sampled := e.stats, e.procs, e.err, e.sampled
+	e.mu.RUnlock()
+
+	if !sampled {
+		w.Header().Set("Retry-After", fmt.Sprint(int(e.opts.Interval.Seconds()+1)))
+		http.Error(w, "no sample yet: the first is taken one interval after startup",
+			http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	writeMetrics(w, stats, procs, e.opts)
+}
+
+// writeMetrics renders one scrape in the Prometheus text exposition format.
+func writeMetrics(w io.Writer, stats sysStatsMsg, procs []ProcessRow, opts metricsOptions) {
+	const gb = 1 << 30
+	host := [][2]string{{"host", stats.Hostname}}
+
+	gauge(w, "gomon_memory_used_bytes", "Physical memory in use.")
+	sample(w, "gomon_memory_used_bytes", host, stats.MemUsed*gb)
+	gauge(w, "gomon_memory_total_bytes", "Total physical memory.")
+	sample(w, "gomon_memory_total_bytes", host, stats.MemTotal*gb)
+	gauge(w, "gomon_uptime_seconds", "Host uptime.")
+	sample(w, "gomon_uptime_seconds", host, float64(stats.UptimeSecs))
+	gauge(w, "gomon_cpu_percent", "Host CPU utilisation across all cores.")
+	sample(w, "gomon_cpu_percent", host, stats.CPUTotal)
+	if stats.HasLoad {
+		gauge(w, "gomon_load_average", "Run-queue load average.")
+		sample(w, "gomon_load_average", [][2]string{{"host", stats.Hostname}, {"period", "1m"}}, stats.Load1)
+		sample(w, "gomon_load_average", [][2]string{{"host", stats.Hostname}, {"period", "5m"}}, stats.Load5)
+		sample(w, "gomon_load_average", [][2]string{{"host", stats.Hostname}, {"period", "15m"}}, stats.Load15)
+	}
+	gauge(w, "gomon_processes", "Number of processes visible to gomon.")
+	sample(w, "gomon_processes", host, float64(len(procs)))
+
+	series := buildSeries(procs, opts)
+
+	gauge(w, "gomon_process_cpu_percent", "Process CPU usage in percent of one core.")
+	for _, s := range series {
+		sample(w, "gomon_process_cpu_percent", s.labels, s.cpu)
+	}
+	gauge(w, "gomon_process_resident_memory_bytes", "Process resident set size.")
+	for _, s := range series {
+		sample(w, "gomon_process_resident_memory_bytes", s.labels, s.rssB)
+	}
+	gauge(w, "gomon_process_threads", "Process OS thread count.")
+	for _, s := range series {
+		sample(w, "gomon_process_threads", s.labels, s.threads)
+	}
+	if opts.Aggregate != "pid" {
+		gauge(w, "gomon_process_count", "Number of processes in the aggregated series.")
+		for _, s := range series {
+			sample(w, "gomon_process_count", s.labels, float64(s.count))
+		}
+	}
+}
+
+// buildSeries applies the allow-list, aggregation and top-N limit.
+func buildSeries(procs []ProcessRow, opts metricsOptions) []metricsSeries {
+	byKey := map[string]*metricsSeries{}
+	var order []string
+	for _, p := range procs {
+		if len(opts.Allow) > 0 && !opts.Allow[p.Name] {
+			continue
+		}
+		var key string
+		var labels [][2]string
+		switch opts.Aggregate {
+		case "name":
+			key, labels = p.Name, [][2]string{{"name", p.Name}}
+		case "user":
+			key, labels = p.User, [][2]string{{"user", p.User}}
+		default:
+			key = fmt.Sprint(p.PID)
+			labels = [][2]string{{"pid", key}, {"name", p.Name}, {"user", p.User}}
+		}
+		s, ok := byKey[key]
+		if !ok {
+			s = &metricsSeries{labels: labels}
+			byKey[key] = s
+			order = append(order, key)
+		}
+		s.cpu += p.CPU
+		s.rssB += p.MemMB * (1 << 20)
+		s.threads += float64(p.Threads)
+		s.count++
+	}
+
+	out := make([]metricsSeries, 0, len(order))
+	for _, k := range order {
+		out = append(out, *byKey[k])
+	}
+	// Busiest first; ties by memory keep the selection stable between scrapes.
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].cpu != out[j].cpu {
+			return out[i].cpu > out[j].cpu
+		}
+		return out[i].rssB > out[j].rssB
+	})
+	if opts.TopN > 0 && len(out) > opts.TopN {
+		out = out[:opts.TopN]
+	}
+	return out
+}
+
+func gauge(w io.Writer, name, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
+
+func sample(w io.Writer, name string, labels [][2]string, v float64) {
+	parts := make([]string, len(labels))
+	for i, l := range labels {
+		parts[i] = l[0] + `="` + escapeLabel(l[1]) + `"`
+	}
+	fmt.Fprintf(w, "%s{%s} %g\n", name, strings.Join(parts, ","), v)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel escapes a label value per the exposition format.
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+// parseAllowList splits a comma-separated list of process names.
+func parseAllowList(s string) map[string]bool {
+	out := map[string]bool{}
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			out[name] = true
+		}
+	}
+	return out
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetricsUnavailableUntilSampled(t *testing.T) {
+	e := &metricsExporter{opts: metricsOptions{Aggregate: "pid", Interval: 2 * time.Second}}
+	scrape := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.serveMetrics(rec, httptest.NewRequest("GET", "/metrics", nil))
+		return rec
+	}
+
+	if rec := scrape(); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("before the first sample: status %d, want 503:\n%s", rec.Code, rec.Body)
+	}
+
+	e.store(goldenSys, processesMsg{Procs: goldenProcs[0]})
+	rec := scrape()
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `gomon_cpu_percent{host="testhost"} 37.5`) {
+		t.Errorf("after a sample: status %d:\n%s", rec.Code, rec.Body)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/shirou/gopsutil/v3/net"
 	"github.com/shirou/gopsutil/v3/process"
 )
 
@@ -24,13 +25,20 @@ const (
 	colMem    = 10
 	colStatus = 8
 	colUser   = 12
-	// separator chars between columns: " │ " = 3 chars each, 5 gaps total
-	colSeparators  = 5 * 3
-	colFixed       = colPID + colCPU + colMem + colStatus + colUser + colSeparators
-	colNameMin     = 10
-	colNameMax     = 40
-	tickInterval   = time.Second
-	highCPUThresh  = 50.0
+	colSpark  = 12
+	// Flexible columns (NAME, COMMAND) share the remaining width; see columnWidths
+	colNameMin = 10
+	colNameMax = 40
+)
+
+// Startup defaults; the config file may override them (see config.go).
+var (
+	tickInterval      = time.Second
+	highCPUThresh     = 50.0
+	defaultSort       = SortCPU
+	defaultSortAsc    = false // CPU descending by default
+	defaultFilter     = ""
+	defaultFilterMode = matchSubstring
 )
 
 // ---------------------------------------------------------------------------
@@ -38,52 +46,140 @@ const (
 // ---------------------------------------------------------------------------
 
 type Model struct {
+	collector Collector
+	replay    *replayCollector // set when collector plays back a recording
+
 	allProcs    []ProcessRow
 	visibleProc []ProcessRow
 
 	sysStats sysStatsMsg
 
-	cursor    int
-	scrollOff int
-	termWidth int
+	// Rolling samples kept across ticks for sparklines
+	procHist   map[int32]*procHistory
+	sysCPUHist *ring
+	sysMemHist *ring
+
+	cursor     int
+	scrollOff  int
+	termWidth  int
 	termHeight int
 
 	sortCol SortColumn
 	sortAsc bool
 
+	refresh  time.Duration // current tick interval, adjustable at runtime
+	tickGen  int           // generation of the live tick chain
+	fetchGen int           // generation of sample fetches; see acceptSample
+	paused   bool          // snapshot frozen; ticks stop until resumed
+
+	colOrder  []SortColumn        // every column, in display/chooser order
+	colShown  map[SortColumn]bool // which of colOrder are visible
+	colCursor int                 // highlighted row in the column chooser
+
+	treeMode  bool
+	collapsed map[int32]bool // PIDs whose subtrees are folded in tree mode
+	treeLines []treeLine     // parallel to visibleProc when treeMode is on
+
 	mode        AppMode
 	filterInput textinput.Model
 	filterText  string
-
-	killTarget *ProcessRow
-	statusMsg  string // ephemeral message in status bar
-	err        error
+	filterQuery *query // parsed filterText; last valid query while editing
+	filterErr   error  // parse error for filterText, shown in the filter bar
+	filterMode  matchMode
+	filterOnCmd bool // bare words match the command line, not the name
+
+	detailPID    int32
+	detail       *ProcessDetail // nil while loading
+	detailErr    error
+	detailScroll int
+
+	connsPID    int32
+	connsName   string
+	conns       []net.ConnectionStat
+	connsErr    error
+	connsLoaded bool
+	connsScroll int
+
+	filesPID    int32
+	filesName   string
+	files       []process.OpenFilesStat
+	filesLimit  uint64 // RLIMIT_NOFILE soft limit; 0 if unknown
+	filesErr    error
+	filesLoaded bool
+	filesScroll int
+
+	baseline   []ProcessRow // snapshot the table is compared with; nil if none
+	baselineAt time.Time
+	baseDiff   snapshotDiff   // baseline → current snapshot
+	baseNew    map[int32]bool // PIDs started since the baseline
+	baseGone   []ProcessRow   // exited since the baseline, filtered and in table order
+	diffScroll int
+	helpScroll int
+
+	alerts      *alerter // nil when no rules are configured
+	alertScroll int
+
+	marked        map[int32]bool // multi-selection, keyed by PID
+	stopped       map[int32]bool // PIDs gomon has sent SIGSTOP and not resumed
+	killTargets   []ProcessRow   // processes the signal picker will act on
+	killTree      bool           // picker acts on whole trees (killTargets leaves-first)
+	killDepth     map[int32]int  // tree depth of each target; nil while listing
+	killResults   []killResultMsg
+	reniceTargets []ProcessRow // processes the renice prompt will act on
+	reniceInput   textinput.Model
+	reniceCur     map[int32]int32 // current nice values; nil while loading
+	reniceErr     string          // validation message under the prompt
+	sigCursor     int             // highlighted entry in the signal picker
+	lastSig       int             // signalOptions index last sent; picker reopens on it
+	statusMsg     string          // ephemeral message in status bar
+	err           error
 }
 
 // ---------------------------------------------------------------------------
 // Init
 // ---------------------------------------------------------------------------
 
-func NewModel() Model {
+func NewModel(c Collector) Model {
 	ti := textinput.New()
-	ti.Placeholder = "type to filter"
-	ti.CharLimit = 64
-	ti.Width = 30
-
-	return Model{
-		termWidth:  120,
-		termHeight: 30,
-		sortCol:    SortCPU,
-		sortAsc:    false, // CPU descending by default
+	ti.Placeholder = "name or query, e.g. user:root cpu>10"
+	ti.CharLimit = 256
+	ti.Width = 40
+
+	order, shown := defaultColumnLayout()
+
+	m := Model{
+		collector:   c,
+		colOrder:    order,
+		colShown:    shown,
+		termWidth:   120,
+		termHeight:  30,
+		sortCol:     defaultSort,
+		sortAsc:     defaultSortAsc,
+		refresh:     tickInterval,
 		filterInput: ti,
+		filterMode:  defaultFilterMode,
+		collapsed:   map[int32]bool{},
+		marked:      map[int32]bool{},
+		stopped:     map[int32]bool{},
+		procHist:    map[int32]*procHistory{},
+		sysCPUHist:  newRing(historyLen),
+		sysMemHist:  newRing(historyLen),
+		lastSig:     defaultSignal,
+		alerts:      newAlerter(alertRules),
+	}
+	m.replay, _ = c.(*replayCollector)
+	if defaultFilter != "" {
+		m.filterInput.SetValue(defaultFilter)
+		m.setFilter(defaultFilter) // validated when the config was loaded
 	}
+	return m
 }
 
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
-		tickCmd(),
-		fetchSysStats(),
-		fetchProcesses(),
+		tickCmd(m.refresh, m.tickGen),
+		m.fetchSysStats(),
+		m.fetchProcesses(),
 	)
 }
 
@@ -91,32 +187,40 @@ func (m Model) Init() tea.Cmd {
 // Commands
 // ---------------------------------------------------------------------------
 
-func tickCmd() tea.Cmd {
-	return tea.Tick(tickInterval, func(t time.Time) tea.Msg {
-		return tickMsg(t)
+func tickCmd(d time.Duration, gen int) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return tickMsg{at: t, gen: gen}
 	})
 }
 
-func fetchSysStats() tea.Cmd {
+func (m *Model) fetchSysStats() tea.Cmd {
+	c, gen := m.collector, m.fetchGen
 	return func() tea.Msg {
-		return CollectSysStats()
+		msg := c.SysStats()
+		msg.gen = gen
+		return msg
 	}
 }
 
-func fetchProcesses() tea.Cmd {
+// fetchProcesses samples the processes with the fields the current view
+// needs.
+func (m *Model) fetchProcesses() tea.Cmd {
+	c, fields, gen := m.collector, m.neededFields(), m.fetchGen
 	return func() tea.Msg {
-		return CollectProcesses()
+		msg := c.Processes(fields)
+		msg.gen = gen
+		return msg
 	}
 }
 
-func killProcess(pid int32) tea.Cmd {
+func killProcess(pid int32, sig signalOption) tea.Cmd {
 	return func() tea.Msg {
 		p, err := process.NewProcess(pid)
 		if err != nil {
-			return killResultMsg{PID: pid, Err: err}
+			return killResultMsg{PID: pid, Signal: sig.Name, Err: err}
 		}
-		err = p.Kill()
-		return killResultMsg{PID: pid, Err: err}
+		err = sendSignal(p, sig.Sig)
+		return killResultMsg{PID: pid, Signal: sig.Name, Err: err}
 	}
 }
 
@@ -133,45 +237,152 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tickMsg:
-		return m, tea.Batch(tickCmd(), fetchSysStats(), fetchProcesses())
+		if msg.gen != m.tickGen || m.paused {
+			return m, nil // superseded by a rate change, or frozen
+		}
+		if m.replay != nil {
+			return m, m.replayTick()
+		}
+		cmds := []tea.Cmd{tickCmd(m.refresh, m.tickGen), m.fetchSysStats(), m.fetchProcesses()}
+		switch m.mode {
+		case ModeDetail:
+			cmds = append(cmds, m.detailCmd(m.detailPID))
+		case ModeConnections:
+			cmds = append(cmds, fetchConns(m.connsPID))
+		case ModeFiles:
+			cmds = append(cmds, fetchFiles(m.filesPID))
+		}
+		return m, tea.Batch(cmds...)
 
 	case sysStatsMsg:
+		if !m.acceptSample(msg.gen) {
+			return m, nil
+		}
 		m.sysStats = msg
 		if msg.Err != nil {
 			m.err = msg.Err
+			return m, nil
 		}
-		return m, nil
+		m.recordSysHistory(msg)
+		return m, m.checkSysAlerts(msg)
 
 	case processesMsg:
+		if !m.acceptSample(msg.gen) {
+			return m, nil
+		}
 		if msg.Err != nil {
 			m.err = msg.Err
 			return m, nil
 		}
 		m.allProcs = msg.Procs
+		m.recordProcHistory(msg.Procs)
+		m.pruneMarked()
+		m.pruneStopped()
 		m.applyFilterAndSort()
 		m.clampCursor()
+		return m, m.checkProcAlerts(msg.Procs)
+
+	case processDetailMsg:
+		// Ignore late replies for a pane that has since been closed or changed.
+		if m.mode != ModeDetail || msg.PID != m.detailPID {
+			return m, nil
+		}
+		if msg.Detail != nil {
+			msg.Detail.setIORates(m.detail)
+		}
+		m.detail, m.detailErr = msg.Detail, msg.Err
+		return m, nil
+
+	case connsMsg:
+		if m.mode != ModeConnections || msg.PID != m.connsPID {
+			return m, nil
+		}
+		m.conns, m.connsErr, m.connsLoaded = msg.Conns, msg.Err, true
 		return m, nil
 
+	case filesMsg:
+		if m.mode != ModeFiles || msg.PID != m.filesPID {
+			return m, nil
+		}
+		m.files, m.filesLimit, m.filesErr, m.filesLoaded = msg.Files, msg.Limit, msg.Err, true
+		return m, nil
+
+	case killBatchResultMsg:
+		for _, r := range msg.Results {
+			m.trackStopped(r)
+		}
+		m.killTargets, m.killTree, m.killDepth = nil, false, nil
+		m.killResults = msg.Results
+		m.mode = ModeSignalResult
+		if msg.Tree {
+			m.statusMsg = summarizeTreeKill(msg)
+		} else {
+			m.statusMsg = summarizeBatch(msg)
+		}
+		return m, nil
+
+	case treeTargetsMsg:
+		m.handleTreeTargets(msg)
+		return m, nil
+
+	case niceValuesMsg:
+		if m.mode == ModeRenice {
+			m.reniceCur = msg
+		}
+		return m, nil
+
+	case reniceResultMsg:
+		m.mode = ModeNormal
+		m.reniceTargets = nil
+		m.statusMsg = summarizeRenice(msg)
+		if m.paused {
+			return m, nil
+		}
+		return m, m.fetchProcesses()
+
 	case killResultMsg:
+		m.trackStopped(msg)
 		m.mode = ModeNormal
-		m.killTarget = nil
+		m.killTargets = nil
 		if msg.Err != nil {
-			m.statusMsg = fmt.Sprintf("kill PID %d failed: %v", msg.PID, msg.Err)
+			m.statusMsg = fmt.Sprintf("%s to PID %d failed: %v", msg.Signal, msg.PID, msg.Err)
 		} else {
-			m.statusMsg = fmt.Sprintf("killed PID %d", msg.PID)
+			m.statusMsg = fmt.Sprintf("sent %s to PID %d", msg.Signal, msg.PID)
 		}
 		return m, nil
 
+	case alertHookMsg:
+		m.handleAlertHookMsg(msg)
+		return m, nil
+
 	case tea.KeyMsg:
 		switch m.mode {
 		case ModeNormal:
 			return m.handleNormalKey(msg)
 		case ModeFilter:
 			return m.handleFilterKey(msg)
-		case ModeConfirmKill:
-			return m.handleConfirmKey(msg)
+		case ModeSignal:
+			return m.handleSignalKey(msg)
+		case ModeSignalResult:
+			m.mode = ModeNormal
+			m.killResults = nil
+			return m, nil
 		case ModeHelp:
 			return m.handleHelpKey(msg)
+		case ModeDetail:
+			return m.handleDetailKey(msg)
+		case ModeColumns:
+			return m.handleColumnsKey(msg)
+		case ModeRenice:
+			return m.handleReniceKey(msg)
+		case ModeConnections:
+			return m.handleConnsKey(msg)
+		case ModeFiles:
+			return m.handleFilesKey(msg)
+		case ModeDiff:
+			return m.handleDiffKey(msg)
+		case ModeAlerts:
+			return m.handleAlertsKey(msg)
 		}
 	}
 
@@ -185,6 +396,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m Model) handleNormalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	m.statusMsg = "" // clear ephemeral status
 
+	if m.replay != nil {
+		if cmd, ok := m.handleReplayKey(msg.String()); ok {
+			return m, cmd
+		}
+	}
+
 	switch msg.String() {
 	case keyQuit, "ctrl+c":
 		return m, tea.Quit
@@ -200,19 +417,58 @@ func (m Model) handleNormalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.filterInput.Focus()
 		return m, textinput.Blink
 
+	case keyFaster:
+		return m, m.setRefresh(-1)
+
+	case keySlower:
+		return m, m.setRefresh(1)
+
+	case keyPause:
+		return m, m.togglePause()
+
+	case keyRenice:
+		return m, m.openRenice()
+
+	case keySuspend:
+		return m, m.signalTargets("SIGSTOP")
+
+	case keyResume:
+		return m, m.signalTargets("SIGCONT")
+
+	case keyStep:
+		return m, m.step()
+
 	case keyEsc:
 		if m.filterText != "" {
-			m.filterText = ""
+			m.setFilter("")
 			m.filterInput.SetValue("")
 			m.applyFilterAndSort()
 			m.clampCursor()
+		} else if len(m.marked) > 0 {
+			m.marked = map[int32]bool{}
 		}
 
+	case keyMark:
+		m.toggleMark()
+
+	case keyMarkAll:
+		m.markAllVisible()
+
+	case keyMarkInvert:
+		m.invertMarks()
+
 	case keyTab:
-		// Cycle sort column forward; each column gets a sensible default direction
-		next := SortColumn((int(m.sortCol) + 1) % 6)
+		// Cycle sort column forward through the visible columns; each column
+		// gets a sensible default direction
+		cols := m.visibleColumns()
+		next := cols[0]
+		for i, id := range cols {
+			if id == m.sortCol && i+1 < len(cols) {
+				next = cols[i+1]
+			}
+		}
 		m.sortCol = next
-		m.sortAsc = (next == SortPID || next == SortName || next == SortUser)
+		m.sortAsc = next.defaultAsc()
 		m.applyFilterAndSort()
 		m.clampCursor()
 
@@ -230,13 +486,70 @@ func (m Model) handleNormalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.toggleSort(SortUser)
 
 	case keyDel, keyKill:
+		if targets := m.actionTargets(); len(targets) > 0 {
+			m.killTargets = targets
+			m.sigCursor = m.lastSig
+			m.mode = ModeSignal
+		}
+
+	case keyKillTree:
+		return m, m.openKillTree()
+
+	case keyEnter:
 		if len(m.visibleProc) > 0 {
-			target := m.visibleProc[m.cursor]
-			m.killTarget = &target
-			m.mode = ModeConfirmKill
+			m.detailPID = m.visibleProc[m.cursor].PID
+			m.detail, m.detailErr = nil, nil
+			m.detailScroll = 0
+			m.mode = ModeDetail
+			return m, m.detailCmd(m.detailPID)
+		}
+
+	case keyTree:
+		m.treeMode = !m.treeMode
+		var pid int32 = -1
+		if len(m.visibleProc) > 0 {
+			pid = m.visibleProc[m.cursor].PID
+		}
+		m.applyFilterAndSort()
+		m.selectPID(pid)
+
+	case keyCollapse, keyVimCollapse:
+		m.collapseSelected()
+
+	case keyExpand, keyVimExpand:
+		m.expandSelected()
+
+	case keyConns:
+		return m, m.openConns()
+
+	case keyFiles:
+		return m, m.openFiles()
+
+	case keyColumns:
+		m.colCursor = 0
+		m.mode = ModeColumns
+
+	case keyBaseline:
+		m.toggleBaseline()
+
+	case keyBaseDiff:
+		if m.baseline == nil {
+			m.statusMsg = fmt.Sprintf("no baseline — press %s to mark one", keyLabel(keyBaseline))
+		} else {
+			m.diffScroll = 0
+			m.mode = ModeDiff
+		}
+
+	case keyAlerts:
+		if m.alerts == nil {
+			m.statusMsg = "no alert rules — add [[alerts.rule]] tables to the config file"
+		} else {
+			m.alertScroll = 0
+			m.mode = ModeAlerts
 		}
 
 	case keyHelp:
+		m.helpScroll = 0
 		m.mode = ModeHelp
 	}
 
@@ -247,15 +560,22 @@ func (m Model) handleHelpKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case keyHelp, keyEsc, keyQuit:
 		m.mode = ModeNormal
+	case keyUp, keyVimUp:
+		scrollBody(&m.helpScroll, -1, len(m.helpLines()), m.bodyHeight(pageChrome))
+	case keyDown, keyVimDown:
+		scrollBody(&m.helpScroll, 1, len(m.helpLines()), m.bodyHeight(pageChrome))
+	case "ctrl+c":
+		return m, tea.Quit
 	}
 	return m, nil
 }
 
 func (m Model) handleFilterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	before := m.neededFields()
 	switch msg.String() {
 	case keyEsc:
 		m.mode = ModeNormal
-		m.filterText = ""
+		m.setFilter("")
 		m.filterInput.SetValue("")
 		m.filterInput.Blur()
 		m.applyFilterAndSort()
@@ -263,34 +583,88 @@ func (m Model) handleFilterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case keyEnter:
-		m.filterText = m.filterInput.Value()
+		m.setFilter(m.filterInput.Value())
 		m.mode = ModeNormal
 		m.filterInput.Blur()
 		m.applyFilterAndSort()
 		m.clampCursor()
-		return m, nil
+		return m, m.fetchNewFields(before)
+
+	case keyFilterMode, keyFilterTarget:
+		if msg.String() == keyFilterMode {
+			m.filterMode = m.filterMode.next()
+		} else {
+			m.filterOnCmd = !m.filterOnCmd
+		}
+		m.setFilter(m.filterInput.Value())
+		m.applyFilterAndSort()
+		m.clampCursor()
+		return m, m.fetchNewFields(before)
 	}
 
 	var cmd tea.Cmd
 	m.filterInput, cmd = m.filterInput.Update(msg)
-	m.filterText = m.filterInput.Value()
+	m.setFilter(m.filterInput.Value())
 	m.applyFilterAndSort()
 	m.clampCursor()
-	return m, cmd
+	return m, tea.Batch(cmd, m.fetchNewFields(before))
 }
 
-func (m Model) handleConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
+// fetchNewFields samples the processes again when the view now needs
+// fields it didn't before (typing port: or cmd:, or matching the command
+// line), rather than filtering on empty values until the next tick, or
+// for as long as the snapshot is paused.
+func (m *Model) fetchNewFields(before procField) tea.Cmd {
+	if m.neededFields()&^before == 0 {
+		return nil
+	}
+	return m.fetchProcesses()
+}
+
+func (m Model) handleSignalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	switch key {
+	case keyUp, keyVimUp:
+		if m.sigCursor > 0 {
+			m.sigCursor--
+		}
+
+	case keyDown, keyVimDown:
+		if m.sigCursor < len(signalOptions)-1 {
+			m.sigCursor++
+		}
+
 	case keyConfirmY, keyEnter:
-		if m.killTarget != nil {
-			pid := m.killTarget.PID
-			return m, killProcess(pid)
+		sig := signalOptions[m.sigCursor]
+		switch {
+		case m.killTree && m.killDepth == nil:
+			// Still listing descendants; sending now would miss them.
+			return m, nil
+		case m.killTree:
+			m.lastSig = m.sigCursor
+			return m, killTree(m.killTargets, sig)
+		}
+		m.lastSig = m.sigCursor
+		switch len(m.killTargets) {
+		case 0:
+			m.mode = ModeNormal
+		case 1:
+			return m, killProcess(m.killTargets[0].PID, sig)
+		default:
+			return m, killProcesses(m.killTargets, sig)
 		}
-		m.mode = ModeNormal
 
 	case keyConfirmN, keyEsc:
 		m.mode = ModeNormal
-		m.killTarget = nil
+		m.killTargets, m.killTree, m.killDepth = nil, false, nil
+
+	default:
+		// 1–9 jump straight to a signal in the list
+		if len(key) == 1 && key[0] >= '1' && key[0] <= '9' {
+			if i := int(key[0] - '1'); i < len(signalOptions) {
+				m.sigCursor = i
+			}
+		}
 	}
 
 	return m, nil
@@ -306,47 +680,69 @@ func (m *Model) toggleSort(col SortColumn) {
 	} else {
 		m.sortCol = col
 		// Numeric columns default descending (highest first); text columns ascending
-		m.sortAsc = (col == SortPID || col == SortName || col == SortUser)
+		m.sortAsc = col.defaultAsc()
 	}
 	m.applyFilterAndSort()
 	m.clampCursor()
 }
 
-func (m *Model) applyFilterAndSort() {
-	filter := strings.ToLower(m.filterText)
+// setFilter parses text as a filter query. On a parse error the previous
+// valid query stays in effect so the table doesn't flicker while typing.
+func (m *Model) setFilter(text string) error {
+	m.filterText = text
+	q, err := parseQuery(text, matchOptions{mode: m.filterMode, cmdline: m.filterOnCmd})
+	m.filterErr = err
+	if err != nil {
+		return err
+	}
+	m.filterQuery = q
+	return nil
+}
 
+func (m *Model) applyFilterAndSort() {
 	// 1. Filter
 	filtered := make([]ProcessRow, 0, len(m.allProcs))
-	for _, p := range m.allProcs {
-		if filter == "" || strings.Contains(strings.ToLower(p.Name), filter) {
-			filtered = append(filtered, p)
+	for i := range m.allProcs {
+		if m.filterQuery.Match(&m.allProcs[i]) {
+			filtered = append(filtered, m.allProcs[i])
 		}
 	}
 
-	// 2. Sort (stable to avoid jumpiness on equal values)
+	// 2. Sort (stable to avoid jumpiness on equal values). Fuzzy matches
+	// rank by score first, the sort column breaking ties.
+	less := m.compareRows
+	if m.filterMode == matchFuzzy && m.filterQuery != nil && len(m.filterQuery.patterns) > 0 {
+		scores := make(map[int32]int, len(filtered))
+		for i := range filtered {
+			scores[filtered[i].PID] = m.filterQuery.Score(&filtered[i])
+		}
+		less = func(a, b ProcessRow) bool {
+			if sa, sb := scores[a.PID], scores[b.PID]; sa != sb {
+				return sa > sb
+			}
+			return m.compareRows(a, b)
+		}
+	}
 	sort.SliceStable(filtered, func(i, j int) bool {
-		return m.compareRows(filtered[i], filtered[j])
+		return less(filtered[i], filtered[j])
 	})
 
+	if m.baseline != nil {
+		m.updateBaseline(less)
+	}
+
+	// 3. Tree layout (sort order is kept within each sibling group)
+	if m.treeMode {
+		m.visibleProc, m.treeLines = buildTree(filtered, m.collapsed, less)
+		return
+	}
+
 	m.visibleProc = filtered
+	m.treeLines = nil
 }
 
 func (m *Model) compareRows(a, b ProcessRow) bool {
-	var less bool
-	switch m.sortCol {
-	case SortPID:
-		less = a.PID < b.PID
-	case SortName:
-		less = strings.ToLower(a.Name) < strings.ToLower(b.Name)
-	case SortCPU:
-		less = a.CPU < b.CPU
-	case SortMem:
-		less = a.MemMB < b.MemMB
-	case SortThreads:
-		less = a.Threads < b.Threads
-	case SortUser:
-		less = strings.ToLower(a.User) < strings.ToLower(b.User)
-	}
+	less := columnDef(m.sortCol).less(m, a, b)
 	if m.sortAsc {
 		return less
 	}
@@ -391,28 +787,65 @@ func (m *Model) adjustScroll() {
 	}
 }
 
-// tableHeight returns number of data rows visible.
-func (m *Model) tableHeight() int {
-	// total: header(1) + colHeader(1) + separator(1) + table rows + filter(1) + sep(1) + status(1) = 6 fixed rows
-	// + 2 for top/bottom border rows in the full layout
-	reserved := 8
-	h := m.termHeight - reserved
+// Lines a full-screen view has besides the header and its scrolling body:
+// separator, title, separator, footer and one spare, plus a column header
+// in the socket and file lists.
+const (
+	pageChrome = 5
+	listChrome = 6
+)
+
+// bodyHeight is the number of body lines a full-screen view with chrome
+// fixed lines has room for.
+func (m *Model) bodyHeight(chrome int) int {
+	h := m.termHeight - chrome - m.headerHeight()
 	if h < 1 {
 		h = 1
 	}
 	return h
 }
 
-// nameColWidth computes the dynamic Name column width.
-func (m *Model) nameColWidth() int {
-	w := m.termWidth - colFixed - 2 // 2 for left margin
-	if w < colNameMin {
-		w = colNameMin
+// scrollBody moves a full-screen view's scroll offset by delta, keeping
+// the last page of its total lines in view.
+func scrollBody(scroll *int, delta, total, bodyH int) {
+	*scroll += delta
+	if *scroll > total-bodyH {
+		*scroll = total - bodyH
 	}
-	if w > colNameMax {
-		w = colNameMax
+	if *scroll < 0 {
+		*scroll = 0
 	}
-	return w
+}
+
+// renderScrollBody renders bodyH lines of a full-screen view from *scroll,
+// clamping it to the lines there are and padding a short page.
+func renderScrollBody(lines []string, scroll *int, bodyH int) string {
+	if *scroll > len(lines) {
+		*scroll = len(lines)
+	}
+	end := *scroll + bodyH
+	if end > len(lines) {
+		end = len(lines)
+	}
+	var b strings.Builder
+	for _, l := range lines[*scroll:end] {
+		b.WriteString(l)
+		b.WriteString("\n")
+	}
+	b.WriteString(strings.Repeat("\n", bodyH-(end-*scroll)))
+	return b.String()
+}
+
+// tableHeight returns number of data rows visible.
+func (m *Model) tableHeight() int {
+	// total: header(n) + colHeader(1) + separator(1) + table rows + filter(1) + sep(1) + status(1)
+	// + 2 for top/bottom border rows in the full layout
+	reserved := 7 + m.headerHeight()
+	h := m.termHeight - reserved
+	if h < 1 {
+		h = 1
+	}
+	return h
 }
 
 // ---------------------------------------------------------------------------
@@ -423,6 +856,24 @@ func (m Model) View() string {
 	if m.mode == ModeHelp {
 		return m.renderHelpScreen()
 	}
+	if m.mode == ModeDetail {
+		return m.renderDetailScreen()
+	}
+	if m.mode == ModeColumns {
+		return m.renderColumnsScreen()
+	}
+	if m.mode == ModeConnections {
+		return m.renderConnsScreen()
+	}
+	if m.mode == ModeFiles {
+		return m.renderFilesScreen()
+	}
+	if m.mode == ModeDiff {
+		return m.renderDiffScreen()
+	}
+	if m.mode == ModeAlerts {
+		return m.renderAlertsScreen()
+	}
 
 	var b strings.Builder
 
@@ -445,9 +896,15 @@ func (m Model) View() string {
 
 	out := b.String()
 
-	if m.mode == ModeConfirmKill && m.killTarget != nil {
+	if m.mode == ModeSignal && len(m.killTargets) > 0 {
 		out = m.renderKillOverlay(out)
 	}
+	if m.mode == ModeSignalResult {
+		out = m.renderBatchResultOverlay(out)
+	}
+	if m.mode == ModeRenice {
+		out = m.renderReniceOverlay(out)
+	}
 
 	return out
 }
@@ -456,57 +913,33 @@ func (m Model) View() string {
 // Render helpers
 // ---------------------------------------------------------------------------
 
-func (m *Model) renderHeader() string {
-	mem := fmt.Sprintf("%.1f / %.1f GB", m.sysStats.MemUsed, m.sysStats.MemTotal)
-	line := fmt.Sprintf(
-		"%s   host: %s   uptime: %s   RAM: %s",
-		styleHeaderLabel.Render("gomon"),
-		styleHeaderValue.Render(m.sysStats.Hostname),
-		styleHeaderValue.Render(m.sysStats.Uptime),
-		styleHeaderValue.Render(mem),
-	)
-	return styleHeader.Width(m.termWidth).Render(line)
-}
-
 func (m *Model) renderSeparator() string {
 	return styleBorder.Render(strings.Repeat("─", m.termWidth))
 }
 
 func (m *Model) renderColHeader() string {
-	nameW := m.nameColWidth()
-	cols := []struct {
-		col   SortColumn
-		label string
-		width int
-		right bool
-	}{
-		{SortPID, "PID", colPID, true},
-		{SortName, "NAME", nameW, false},
-		{SortCPU, "CPU%", colCPU, true},
-		{SortMem, "MEM(MB)", colMem, true},
-		{SortThreads, "THRD", colStatus, true},
-		{SortUser, "USER", colUser, false},
-	}
+	cols, widths := m.layoutColumns()
 
 	var parts []string
-	for _, c := range cols {
+	for i, id := range cols {
+		c := columnDef(id)
 		indicator := " "
-		if m.sortCol == c.col {
+		if m.sortCol == id {
 			if m.sortAsc {
 				indicator = "▲"
 			} else {
 				indicator = "▼"
 			}
 		}
-		label := c.label + indicator
+		label := c.title + indicator
 		var cell string
 		if c.right {
-			cell = padLeft(label, c.width)
+			cell = padLeft(label, widths[i])
 		} else {
-			cell = padRight(label, c.width)
+			cell = padRight(label, widths[i])
 		}
 
-		if m.sortCol == c.col {
+		if m.sortCol == id {
 			parts = append(parts, styleColHeaderSelected.Render(cell))
 		} else {
 			parts = append(parts, styleColHeader.Render(cell))
@@ -519,63 +952,106 @@ func (m *Model) renderColHeader() string {
 func (m *Model) renderTable() string {
 	var b strings.Builder
 	h := m.tableHeight()
-	nameW := m.nameColWidth()
+	cols, widths := m.layoutColumns()
 
 	for i := 0; i < h; i++ {
 		idx := m.scrollOff + i
 		if idx >= len(m.visibleProc) {
+			// Below the live rows: processes gone since the baseline.
+			if g := idx - len(m.visibleProc); g < len(m.baseGone) {
+				b.WriteString(m.renderExitedRow(idx, m.baseGone[g], cols, widths))
+			}
 			b.WriteString("\n")
 			continue
 		}
 		row := m.visibleProc[idx]
 		selected := idx == m.cursor
 
+		marked := m.marked[row.PID]
+
+		// Every segment is rendered with the row style, so a cell with its
+		// own colour (a filter match, a warning) doesn't end the row's.
+		style := m.rowStyle(row, marked, selected)
+
 		// cursor glyph
-		cursor := " "
+		cursor := style.Render(" ")
 		if selected {
-			cursor = styleCursor.Render("▶")
+			cursor = styleCursor.Inherit(style).Render("▶")
+		} else if marked {
+			cursor = styleMarkGlyph.Inherit(style).Render("•")
+		} else if m.baseNew[row.PID] {
+			cursor = styleDiffStarted.Inherit(style).Render("+")
 		}
 
 		// cells
-		pid := padLeft(fmt.Sprintf("%d", row.PID), colPID)
-		name := truncate(row.Name, nameW)
-		name = padRight(name, nameW)
-		cpu := padLeft(fmt.Sprintf("%.2f", row.CPU), colCPU)
-		memStr := padLeft(fmt.Sprintf("%.1f", row.MemMB), colMem)
-		threads := padLeft(fmt.Sprintf("%d", row.Threads), colStatus)
-		user := padRight(row.User, colUser)
-
-		line := cursor + pid + styleBorder.Render(" │ ") +
-			name + styleBorder.Render(" │ ") +
-			cpu + styleBorder.Render(" │ ") +
-			memStr + styleBorder.Render(" │ ") +
-			threads + styleBorder.Render(" │ ") +
-			user
-
-		highCPU := row.CPU >= highCPUThresh
-
-		if selected {
-			if highCPU {
-				b.WriteString(styleRowHighCPUSelected.Width(m.termWidth).Render(line))
+		hl := m.filterQuery.Highlights(&row)
+		cells := make([]string, len(cols))
+		for c, id := range cols {
+			def := columnDef(id)
+			text := truncate(def.format(m, idx, row), widths[c])
+			if def.right {
+				text = padLeft(text, widths[c])
 			} else {
-				b.WriteString(styleRowSelected.Width(m.termWidth).Render(line))
+				text = padRight(text, widths[c])
 			}
-		} else {
-			if highCPU {
-				b.WriteString(styleRowHighCPU.Render(line))
-			} else {
-				b.WriteString(styleRowNormal.Render(line))
+			switch {
+			case id == SortName && m.filterOnCmd:
+				cells[c] = highlightRunes(text, m.treePrefixLen(idx), cmdNamePositions(hl, row.Cmdline, row.Name), row.Name, style)
+			case id == SortName:
+				cells[c] = highlightRunes(text, m.treePrefixLen(idx), hl, row.Name, style)
+			case id == SortCmdline && m.filterOnCmd:
+				cells[c] = highlightRunes(text, 0, hl, row.Cmdline, style)
+			case def.warn != nil && def.warn(row):
+				cells[c] = styleCellWarn.Inherit(style).Render(text)
+			default:
+				cells[c] = style.Render(text)
 			}
 		}
+		line := cursor + strings.Join(cells, styleBorder.Inherit(style).Render(" │ "))
+		if pad := m.termWidth - lipgloss.Width(line); selected && pad > 0 {
+			line += style.Render(strings.Repeat(" ", pad))
+		}
+		b.WriteString(line)
 		b.WriteString("\n")
 	}
 
 	return b.String()
 }
 
+// rowStyle picks a table row's style. Marks win over state colours, which
+// win over the high-CPU highlight.
+func (m *Model) rowStyle(row ProcessRow, marked, selected bool) lipgloss.Style {
+	pick := func(normal, sel lipgloss.Style) lipgloss.Style {
+		if selected {
+			return sel
+		}
+		return normal
+	}
+	switch {
+	case marked:
+		return pick(styleRowMarked, styleRowMarkedSelected)
+	case m.isStopped(row):
+		return pick(styleRowStopped, styleRowStoppedSelected)
+	case row.State == "Z":
+		return pick(styleRowZombie, styleRowZombieSelected)
+	case row.State == "D":
+		return pick(styleRowBlocked, styleRowBlockedSelected)
+	case row.CPU >= highCPUThresh:
+		return pick(styleRowHighCPU, styleRowHighCPUSelected)
+	}
+	return pick(styleRowNormal, styleRowSelected)
+}
+
 func (m *Model) renderFilterBar() string {
-	label := styleFilterLabel.Render("  Filter: ")
+	target := "name"
+	if m.filterOnCmd {
+		target = "cmd"
+	}
+	label := styleFilterLabel.Render(fmt.Sprintf("  Filter (%s·%s): ", m.filterMode, target))
 	hint := styleFilterHint.Render("   Esc clear · Enter confirm")
+	if m.mode == ModeFilter {
+		hint = styleFilterHint.Render("   Tab mode · Ctrl+T name/cmd · Esc clear · Enter confirm")
+	}
 
 	var inputView string
 	if m.mode == ModeFilter {
@@ -585,22 +1061,38 @@ func (m *Model) renderFilterBar() string {
 	} else {
 		inputView = "[          ]"
 	}
+	if m.filterErr != nil {
+		hint = styleFilterError.Render("   ✗ " + m.filterErr.Error())
+	}
 
 	return label + inputView + hint
 }
 
 func (m *Model) renderStatusBar() string {
-	if m.statusMsg != "" {
-		return styleStatusError.Render("  " + m.statusMsg)
+	var line string
+	switch {
+	case m.statusMsg != "":
+		line = styleStatusError.Render("  " + m.statusMsg)
+	case m.err != nil:
+		line = styleStatusError.Render("  Error: " + m.err.Error())
+	case len(m.alerts.active()) > 0:
+		line = styleStatusError.Render(fmt.Sprintf("  %s   (%s: alerts)",
+			m.alerts.active()[0].message(), keyLabel(keyAlerts)))
+	default:
+		line = styleStatusBar.Render("  " + helpText)
 	}
-	if m.err != nil {
-		return styleStatusError.Render("  Error: " + m.err.Error())
+	if ind := m.renderStoppedIndicator(); ind != "" {
+		line = "  " + ind + line
 	}
-	return styleStatusBar.Render("  " + helpText)
+	if badge := m.renderAlertBadge(); badge != "" {
+		line = "  " + badge + line
+	}
+	// Clip rather than wrap so the footer stays one line.
+	return lipgloss.NewStyle().MaxWidth(m.termWidth).Render(line)
 }
 
 func (m *Model) renderKillOverlay(base string) string {
-	if m.killTarget == nil {
+	if len(m.killTargets) == 0 {
 		return base
 	}
 
@@ -609,11 +1101,41 @@ func (m *Model) renderKillOverlay(base string) string {
 		windowsNote = "\n  " + styleOverlayHint.Render("(Windows: force-terminate, no SIGTERM)")
 	}
 
-	content := styleOverlayTitle.Render("Kill Process?") + "\n\n" +
-		fmt.Sprintf("  Kill PID %d (%s)\n", m.killTarget.PID, m.killTarget.Name) +
-		fmt.Sprintf("  owned by: %s", m.killTarget.User) +
+	var list strings.Builder
+	for i, s := range signalOptions {
+		line := fmt.Sprintf("%d %-8s %s", i+1, s.Name, s.Desc)
+		if i == m.sigCursor {
+			list.WriteString(styleCursor.Render("▶ ") + styleOverlaySelected.Render(line))
+		} else {
+			list.WriteString("  " + line)
+		}
+		list.WriteString("\n")
+	}
+
+	title := "Send Signal"
+	var target string
+	if m.killTree {
+		title = "Signal Process Tree"
+		// Leave room for the signal list, border, title and hints.
+		max := m.termHeight - len(signalOptions) - 14
+		if max < 3 {
+			max = 3
+		}
+		target = m.renderTreeTargets(max)
+	} else if len(m.killTargets) == 1 {
+		t := m.killTargets[0]
+		target = fmt.Sprintf("  PID %d (%s)\n", t.PID, t.Name) +
+			fmt.Sprintf("  owned by: %s", t.User)
+	} else {
+		target = fmt.Sprintf("  %d marked processes:\n", len(m.killTargets)) +
+			m.renderTargetList(8)
+	}
+
+	content := styleOverlayTitle.Render(title) + "\n\n" +
+		target +
 		windowsNote + "\n\n" +
-		"  " + styleOverlayHint.Render("Press y to confirm, n or Esc to cancel")
+		list.String() + "\n" +
+		"  " + styleOverlayHint.Render("↑/↓ or 1–9 choose · y/Enter send · n/Esc cancel")
 
 	box := styleOverlayBorder.Render(content)
 
@@ -642,69 +1164,138 @@ func (m *Model) renderHelpScreen() string {
 	b.WriteString(styleHelpTitle.Render(indent + "gomon — keyboard reference"))
 	b.WriteString("\n")
 
+	// Body scrolls between the title and the footer, like the detail pane.
+	b.WriteString(renderScrollBody(m.helpLines(), &m.helpScroll, m.bodyHeight(pageChrome)))
+
+	b.WriteString(m.renderSeparator())
+	b.WriteString("\n")
+	b.WriteString(styleStatusBar.Render(indent + scrollHelp() + "  ·  " +
+		keysLabel(keyHelp, keyEsc, keyQuit) + " close help"))
+
+	return b.String()
+}
+
+// helpLines is the body of the help screen: one section per feature, then
+// the visible columns.
+func (m *Model) helpLines() []string {
+	var b strings.Builder
+	indent := "  "
+
 	type row struct{ key, desc string }
 	section := func(title string, rows []row) {
 		b.WriteString("\n")
 		b.WriteString(styleHelpSection.Render(indent + title))
 		b.WriteString("\n")
+		descW := m.termWidth - len(indent) - 2 - 14
+		if descW < 4 {
+			descW = 4
+		}
 		for _, r := range rows {
 			k := styleHelpKey.Render(padRight(r.key, 14))
-			d := styleHelpDesc.Render(r.desc)
+			d := styleHelpDesc.Render(truncate(r.desc, descW))
 			b.WriteString(indent + "  " + k + d + "\n")
 		}
 	}
 
 	section("Navigation", []row{
-		{"j / ↓", "Move cursor down"},
-		{"k / ↑", "Move cursor up"},
-		{"q", "Quit gomon"},
+		{keyLabel(keyVimDown) + " / " + keyLabel(keyDown), "Move cursor down"},
+		{keyLabel(keyVimUp) + " / " + keyLabel(keyUp), "Move cursor up"},
+		{keyLabel(keyQuit), "Quit gomon"},
 		{"Ctrl+C", "Force quit"},
 	})
 
 	section("Filter", []row{
-		{"/", "Enter filter mode — type to search by process name"},
-		{"Esc", "Clear filter and return to normal mode"},
-		{"Enter", "Confirm filter and return to normal mode"},
+		{keyLabel(keyFilter), "Enter filter mode — a bare word matches the process name"},
+		{keyLabel(keyEsc), "Clear filter and return to normal mode"},
+		{keyLabel(keyEnter), "Confirm filter and return to normal mode"},
+		{keyLabel(keyFilterMode), "Cycle bare-word matching: substring → regex → fuzzy"},
+		{keyLabel(keyFilterTarget), "Match bare words against name or full command line"},
+		{"field:value", "Contains (text) or equals (number): user:root pid:42"},
+		{"< <= > >= =", "Compare: cpu>20 mem>=500MB threads=1"},
+		{"~  !~", "Regex match: name~^java cmd!~--daemon"},
+		{"AND OR NOT ()", "Combine terms; space means AND"},
+		{"port:N", "Processes listening on port N (TCP) or bound to it (UDP)"},
+		{"Fields", "name cmd user group state tty pid ppid cpu mem vsz threads nice fds port"},
+	})
+
+	section("Refresh", []row{
+		{keyLabel(keyFaster) + " / " + keyLabel(keySlower), "Refresh faster / slower (250ms – 10s)"},
+		{keyLabel(keyPause), "Pause — freeze the snapshot; navigate, sort and filter it"},
+		{keyLabel(keyStep), "While paused, take exactly one new sample"},
+	})
+
+	if m.replay != nil {
+		section("Replay", []row{
+			{keyLabel(keyFaster) + " / " + keyLabel(keySlower), "Play faster / slower (0.25x – 64x)"},
+			{keyLabel(keyPause), "Play / pause"},
+			{keyLabel(keyStep), "While paused, go to the next recorded sample"},
+			{keyLabel(keySeekBack) + " / " + keyLabel(keySeekFwd), "Seek 10s back / forward"},
+			{keyLabel(keySeekBackFar) + " / " + keyLabel(keySeekFwdFar), "Seek 1 min back / forward"},
+		})
+	}
+
+	section("Tree", []row{
+		{keyLabel(keyTree), "Toggle process tree (children indented under parents)"},
+		{keyLabel(keyCollapse) + " / " + keyLabel(keyVimCollapse), "Collapse subtree (or parent of a leaf)"},
+		{keyLabel(keyExpand) + " / " + keyLabel(keyVimExpand), "Expand subtree"},
 	})
 
 	section("Sorting", []row{
-		{"Tab", "Cycle sort column forward"},
-		{"1", "Sort by PID (ascending)"},
-		{"2", "Sort by Name (A→Z)"},
-		{"3", "Sort by CPU%   (default — highest first)"},
-		{"4", "Sort by Memory in MB (highest first)"},
-		{"5", "Sort by Thread count (highest first)"},
-		{"6", "Sort by User (A→Z)"},
+		{keyLabel(keyTab), "Cycle sort column forward"},
+		{keyLabel(keySortPID), "Sort by PID (ascending)"},
+		{keyLabel(keySortName), "Sort by Name (A→Z)"},
+		{keyLabel(keySortCPU), "Sort by CPU%   (default — highest first)"},
+		{keyLabel(keySortMem), "Sort by Memory in MB (highest first)"},
+		{keyLabel(keySortStatus), "Sort by Thread count (highest first)"},
+		{keyLabel(keySortUser), "Sort by User (A→Z)"},
+		{keyLabel(keyColumns), "Choose columns — show, hide, reorder, sort by any column"},
+	})
+
+	section("Selection", []row{
+		{keyLabel(keyMark), "Mark / unmark the selected process"},
+		{keyLabel(keyMarkAll), "Mark every visible process (respects the filter)"},
+		{keyLabel(keyMarkInvert), "Invert marks on visible processes"},
+		{keyLabel(keyEsc), "Clear marks (when no filter is active)"},
 	})
 
 	section("Process Actions", []row{
-		{"Del / K", "Kill selected process — shows confirmation dialog"},
-		{"y / Enter", "Confirm kill"},
-		{"n / Esc", "Cancel kill"},
+		{keyLabel(keyEnter), "Show details — command line, cwd, disk I/O, limits, environment"},
+		{keyLabel(keyConns), "Show sockets — TCP/UDP/unix, addresses, state; listeners first"},
+		{keyLabel(keyFiles), "Show open files — every descriptor with its path, against the limit"},
+		{keyLabel(keyDel) + " / " + keyLabel(keyKill), "Send a signal to the marked processes, or the selected one"},
+		{keyLabel(keyKillTree), "Signal the whole tree under the selection, leaves first; survivors are reported"},
+		{"↑ / ↓  1–9", "Choose signal (the last one sent is pre-selected)"},
+		{keyLabel(keyConfirmY) + " / " + keyLabel(keyEnter), "Send chosen signal"},
+		{keyLabel(keyConfirmN) + " / " + keyLabel(keyEsc), "Cancel"},
+		{keyLabel(keySuspend), "Suspend (SIGSTOP) the marked processes, or the selected one"},
+		{keyLabel(keyResume), "Resume (SIGCONT) — stopped rows are dimmed and listed in the footer"},
+		{keyLabel(keyRenice), "Renice the marked processes, or the selected one (-20 … 19)"},
 	})
 
-	section("Columns", []row{
-		{"PID", "Process ID assigned by the operating system"},
-		{"NAME", "Executable name (truncated with … if longer than column)"},
-		{"CPU%", "CPU usage across all cores — can exceed 100% on multi-core"},
-		{"", "  First tick always shows 0% — real values appear after ~1s"},
-		{"MEM(MB)", "Resident set size: physical RAM in use, in megabytes"},
-		{"THRD", "Number of OS threads owned by the process"},
-		{"USER", "Account that owns the process (N/A if access is denied)"},
+	section("Baseline", []row{
+		{keyLabel(keyBaseline), "Mark the current snapshot as a baseline (again to clear)"},
+		{"", "  New processes are marked +, exited ones listed − below the table"},
+		{keyLabel(keyBaseDiff), "Report started, exited and biggest CPU / memory / thread changes"},
 	})
 
-	// Pad remaining lines so the status bar sits at the bottom
-	built := b.String()
-	lines := strings.Count(built, "\n")
-	for i := lines; i < m.termHeight-2; i++ {
-		b.WriteString("\n")
+	if m.alerts != nil {
+		section("Alerts", []row{
+			{keyLabel(keyAlerts), "Show firing alerts, the alert log and the rules from the config file"},
+		})
 	}
 
-	b.WriteString(m.renderSeparator())
-	b.WriteString("\n")
-	b.WriteString(styleStatusBar.Render(indent + "Press  ?  Esc  or  q  to close help"))
+	// Describe the visible columns; the chooser (c) lists the rest.
+	var colRows []row
+	for _, id := range m.visibleColumns() {
+		c := columnDef(id)
+		colRows = append(colRows, row{c.title, c.help})
+		if id == SortCPU {
+			colRows = append(colRows, row{"", "  First tick always shows 0% — real values appear after ~1s"})
+		}
+	}
+	section("Columns", colRows)
 
-	return b.String()
+	return strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
 }
 
 // ---------------------------------------------------------------------------
@@ -737,4 +1328,3 @@ func truncate(s string, max int) string {
 	}
 	return string(runes[:max-3]) + "..."
 }
-
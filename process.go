@@ -1,9 +1,12 @@
 package main
 
 import (
+	"fmt"
 	"math"
 	"os"
+	"os/user"
 	"sync"
+	"time"
 
 	"github.com/shirou/gopsutil/v3/process"
 )
@@ -11,14 +14,47 @@ import (
 // procCache retains *process.Process objects between ticks so that
 // p.Percent(0) can measure a real CPU delta (it needs two calls on the same
 // object — the first seeds the baseline, the second returns the delta).
+// ioCache does the same for disk I/O byte counters, and ppidCache keeps the
+// parent each PID had on the last tick.
 var (
 	procCache   = map[int32]*process.Process{}
+	ioCache     = map[int32]ioSample{}
+	ppidCache   = map[int32]int32{}
+	groupNames  = map[int32]string{}
 	procCacheMu sync.Mutex
 )
 
-// CollectProcesses returns a snapshot of all running processes.
-// Real CPU% values appear from the second tick onward (~1 s after start).
-func CollectProcesses() processesMsg {
+// ioSample is the last cumulative I/O reading for one PID.
+type ioSample struct {
+	read, write uint64
+	at          time.Time
+}
+
+// procField selects optional per-process fields. The base columns (name,
+// PPID, CPU, RSS, VSZ, threads, user) are always collected; anything more
+// costly is only read when a visible column or the sort key needs it.
+type procField uint32
+
+const (
+	fieldCmdline procField = 1 << iota
+	fieldState
+	fieldNice
+	fieldShared
+	fieldTimes
+	fieldStart
+	fieldFDs
+	fieldIO
+	fieldGroup
+	fieldTTY
+	fieldPorts
+
+	fieldNone procField = 0
+)
+
+// CollectProcesses returns a snapshot of all running processes, reading the
+// optional fields selected by fields.
+// Real CPU% and I/O rates appear from the second tick onward (~1 s after start).
+func CollectProcesses(fields procField) processesMsg {
 	selfPID := int32(os.Getpid())
 
 	pids, err := process.Pids()
@@ -37,9 +73,17 @@ func CollectProcesses() processesMsg {
 	for pid := range procCache {
 		if _, alive := livePIDs[pid]; !alive {
 			delete(procCache, pid)
+			delete(ioCache, pid)
+			delete(ppidCache, pid)
 		}
 	}
 
+	var ports map[int32][]uint32
+	if fields&fieldPorts != 0 {
+		ports = listeningPorts()
+	}
+
+	now := time.Now()
 	rows := make([]ProcessRow, 0, len(pids))
 	for _, pid := range pids {
 		if pid == selfPID {
@@ -47,8 +91,8 @@ func CollectProcesses() processesMsg {
 		}
 
 		// Reuse cached object so CPU baseline persists between ticks.
-		p, ok := procCache[pid]
-		if !ok {
+		p, cached := procCache[pid]
+		if !cached {
 			p, err = process.NewProcess(pid)
 			if err != nil {
 				continue
@@ -56,22 +100,49 @@ func CollectProcesses() processesMsg {
 			procCache[pid] = p
 		}
 
-		name, err := p.Name()
-		if err != nil || name == "" {
-			continue // kernel/zombie process we can't read
-		}
-
 		// Percent(0) is non-blocking: call 1 seeds baseline (returns 0),
 		// call 2+ returns real delta since last call.
 		cpuPct, err := p.Percent(0)
-		if err != nil || math.IsNaN(cpuPct) || math.IsInf(cpuPct, 0) || cpuPct < 0 {
+		if err != nil {
+			cpuPct = 0
+		}
+
+		// Parent PID may be unreadable for short-lived or protected processes;
+		// 0 makes the row a root in tree mode.
+		ppid, err := p.Ppid()
+		if err != nil {
+			ppid = 0
+		}
+
+		// CPU time running backwards or a new parent can mean the cached
+		// handle's process exited and its PID went to a newer one. Only
+		// then is the start time compared, through a fresh handle.
+		if cached && (cpuPct < 0 || ppid != ppidCache[pid]) {
+			if fresh := recycledHandle(p); fresh != nil {
+				p = fresh
+				procCache[pid] = p
+				delete(ioCache, pid)
+				cpuPct, _ = p.Percent(0) // seeds the new baseline
+				if ppid, err = p.Ppid(); err != nil {
+					ppid = 0
+				}
+			}
+		}
+		ppidCache[pid] = ppid
+		if math.IsNaN(cpuPct) || math.IsInf(cpuPct, 0) || cpuPct < 0 {
 			cpuPct = 0
 		}
 
+		name, err := p.Name()
+		if err != nil || name == "" {
+			continue // kernel/zombie process we can't read
+		}
+
 		memInfo, err := p.MemoryInfo()
-		var memMB float64
+		var memMB, vszMB float64
 		if err == nil && memInfo != nil {
 			memMB = float64(memInfo.RSS) / (1 << 20)
+			vszMB = float64(memInfo.VMS) / (1 << 20)
 		}
 
 		threads, err := p.NumThreads()
@@ -92,15 +163,141 @@ func CollectProcesses() processesMsg {
 			}
 		}
 
-		rows = append(rows, ProcessRow{
+		row := ProcessRow{
 			PID:     pid,
+			PPID:    ppid,
 			Name:    name,
 			CPU:     cpuPct,
 			MemMB:   memMB,
+			VSZMB:   vszMB,
 			Threads: threads,
 			User:    username,
-		})
+		}
+		collectOptional(p, &row, fields, now)
+		row.Ports = ports[pid]
+		rows = append(rows, row)
 	}
 
 	return processesMsg{Procs: rows}
 }
+
+// recycledHandle returns a new handle for p's PID if it now belongs to a
+// newer process than the one p was opened for, or nil if it doesn't.
+// gopsutil caches the start time in p, so the current one is read through
+// the new handle.
+func recycledHandle(p *process.Process) *process.Process {
+	was, err := p.CreateTime()
+	if err != nil {
+		return nil
+	}
+	fresh, err := process.NewProcess(p.Pid)
+	if err != nil {
+		return nil
+	}
+	if now, err := fresh.CreateTime(); err != nil || now == was {
+		return nil
+	}
+	return fresh
+}
+
+// collectOptional fills the fields requested by the caller. Every read is
+// best-effort: a field that can't be read keeps its zero value.
+// Must be called with procCacheMu held.
+func collectOptional(p *process.Process, row *ProcessRow, fields procField, now time.Time) {
+	if fields&fieldCmdline != 0 {
+		row.Cmdline, _ = p.Cmdline()
+	}
+	if fields&fieldState != 0 {
+		if st, err := p.Status(); err == nil && len(st) > 0 {
+			row.State = stateLetter(st[0])
+		}
+	}
+	if fields&fieldNice != 0 {
+		if nice, err := processNice(p); err == nil {
+			row.Nice = nice
+			// Matches top's PR column for normal (non-realtime) tasks.
+			row.Priority = 20 + nice
+		}
+	}
+	if fields&fieldShared != 0 {
+		if shr, err := sharedMemBytes(p); err == nil {
+			row.SharedMB = float64(shr) / (1 << 20)
+		}
+	}
+	if fields&fieldTimes != 0 {
+		if t, err := p.Times(); err == nil {
+			row.CPUTime = t.User + t.System
+		}
+	}
+	if fields&fieldStart != 0 {
+		if ms, err := p.CreateTime(); err == nil {
+			row.Started = time.UnixMilli(ms)
+		}
+	}
+	if fields&fieldFDs != 0 {
+		row.FDs, _ = p.NumFDs()
+		row.FDLimit = nofileLimit(p)
+	}
+	if fields&fieldIO != 0 {
+		if io, err := p.IOCounters(); err == nil {
+			prev, seen := ioCache[row.PID]
+			if dt := now.Sub(prev.at).Seconds(); seen && dt > 0 &&
+				io.ReadBytes >= prev.read && io.WriteBytes >= prev.write {
+				row.ReadBps = float64(io.ReadBytes-prev.read) / dt
+				row.WriteBps = float64(io.WriteBytes-prev.write) / dt
+			}
+			ioCache[row.PID] = ioSample{read: io.ReadBytes, write: io.WriteBytes, at: now}
+		}
+	}
+	if fields&fieldGroup != 0 {
+		if gids, err := p.Gids(); err == nil && len(gids) > 0 {
+			// Linux reports real, effective, saved, fs; show effective like ps.
+			gid := gids[0]
+			if len(gids) > 1 {
+				gid = gids[1]
+			}
+			row.Group = groupName(gid)
+		}
+	}
+	if fields&fieldTTY != 0 {
+		row.TTY, _ = p.Terminal()
+	}
+}
+
+// groupName resolves a GID, caching lookups across ticks.
+// Must be called with procCacheMu held.
+func groupName(gid int32) string {
+	if name, ok := groupNames[gid]; ok {
+		return name
+	}
+	name := fmt.Sprint(gid)
+	if g, err := user.LookupGroupId(name); err == nil {
+		name = g.Name
+	}
+	groupNames[gid] = name
+	return name
+}
+
+// stateLetter maps gopsutil's status names back to the ps(1) state codes.
+func stateLetter(status string) string {
+	switch status {
+	case process.Running:
+		return "R"
+	case process.Sleep:
+		return "S"
+	case process.Blocked:
+		return "D"
+	case process.Stop:
+		return "T"
+	case process.Zombie:
+		return "Z"
+	case process.Idle:
+		return "I"
+	case process.Wait:
+		return "W"
+	case process.Lock:
+		return "L"
+	default:
+		return "?"
+	}
+}
@@ -0,0 +1,486 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ---------------------------------------------------------------------------
+// Filter query language
+//
+//   query   := or
+//   or      := and { ("OR" | "||") and }
+//   and     := not { ["AND" | "&&"] not }      juxtaposition means AND
+//   not     := ("NOT" | "!") not | primary
+//   primary := "(" query ")" | term
+//   term    := field op value | word           a bare word matches NAME
+//
+// Bare words are matched by the active matchMode (substring, regex or
+// fuzzy) against the name or, optionally, the full command line; a word that
+// starts with a double quote is always bare, so "a:b" searches for a:b.
+//
+// Operators: ":" contains (text) / equals (numbers), "=" and "!=" exact,
+// "~" and "!~" regular expression, "<" "<=" ">" ">=" numeric comparison.
+// Text matching is case-insensitive. Memory values accept B/KB/MB/GB
+// suffixes (MB if omitted). Values containing spaces or parentheses must be
+// double-quoted, e.g. name~"^(java|node)$".
+//
+//   user:postgres cpu>20 mem>=500MB
+//   name~^java OR (state:Z AND NOT user:root)
+//   port:8080                                  who listens on 8080
+// ---------------------------------------------------------------------------
+
+// queryNode is one node of a parsed filter expression.
+type queryNode interface {
+	match(r *ProcessRow) bool
+}
+
+type andNode struct{ l, r queryNode }
+type orNode struct{ l, r queryNode }
+type notNode struct{ n queryNode }
+
+func (n andNode) match(r *ProcessRow) bool { return n.l.match(r) && n.r.match(r) }
+func (n orNode) match(r *ProcessRow) bool  { return n.l.match(r) || n.r.match(r) }
+func (n notNode) match(r *ProcessRow) bool { return !n.n.match(r) }
+
+// textTerm compares a string field.
+type textTerm struct {
+	get func(r *ProcessRow) string
+	op  string
+	val string // lower-cased
+	re  *regexp.Regexp
+}
+
+func (t textTerm) match(r *ProcessRow) bool {
+	v := t.get(r)
+	switch t.op {
+	case "~":
+		return t.re.MatchString(v)
+	case "!~":
+		return !t.re.MatchString(v)
+	case "=":
+		return strings.EqualFold(v, t.val)
+	case "!=":
+		return !strings.EqualFold(v, t.val)
+	default: // ":"
+		return strings.Contains(strings.ToLower(v), t.val)
+	}
+}
+
+// numTerm compares a numeric field.
+type numTerm struct {
+	get func(r *ProcessRow) float64
+	op  string
+	val float64
+}
+
+func (t numTerm) match(r *ProcessRow) bool {
+	return compareNum(t.get(r), t.op, t.val)
+}
+
+// listTerm compares a field with several numeric values, such as the ports
+// a process listens on. It matches if any value does; "!=" matches if none
+// is equal.
+type listTerm struct {
+	get func(r *ProcessRow) []float64
+	op  string
+	val float64
+}
+
+func (t listTerm) match(r *ProcessRow) bool {
+	vs := t.get(r)
+	if t.op == "!=" {
+		for _, v := range vs {
+			if v == t.val {
+				return false
+			}
+		}
+		return true
+	}
+	for _, v := range vs {
+		if compareNum(v, t.op, t.val) {
+			return true
+		}
+	}
+	return false
+}
+
+func compareNum(v float64, op string, val float64) bool {
+	switch op {
+	case "<":
+		return v < val
+	case "<=":
+		return v <= val
+	case ">":
+		return v > val
+	case ">=":
+		return v >= val
+	case "!=":
+		return v != val
+	default: // ":" and "="
+		return v == val
+	}
+}
+
+// queryField describes one field usable in a term.
+type queryField struct {
+	text   func(r *ProcessRow) string
+	num    func(r *ProcessRow) float64
+	nums   func(r *ProcessRow) []float64 // several values; any may match
+	memory bool                          // value is MB and accepts size suffixes
+	fields procField
+}
+
+var queryFields = map[string]queryField{
+	"name":    {text: func(r *ProcessRow) string { return r.Name }},
+	"cmd":     {text: func(r *ProcessRow) string { return r.Cmdline }, fields: fieldCmdline},
+	"user":    {text: func(r *ProcessRow) string { return r.User }},
+	"group":   {text: func(r *ProcessRow) string { return r.Group }, fields: fieldGroup},
+	"state":   {text: func(r *ProcessRow) string { return r.State }, fields: fieldState},
+	"tty":     {text: func(r *ProcessRow) string { return r.TTY }, fields: fieldTTY},
+	"pid":     {num: func(r *ProcessRow) float64 { return float64(r.PID) }},
+	"ppid":    {num: func(r *ProcessRow) float64 { return float64(r.PPID) }},
+	"cpu":     {num: func(r *ProcessRow) float64 { return r.CPU }},
+	"mem":     {num: func(r *ProcessRow) float64 { return r.MemMB }, memory: true},
+	"vsz":     {num: func(r *ProcessRow) float64 { return r.VSZMB }, memory: true},
+	"threads": {num: func(r *ProcessRow) float64 { return float64(r.Threads) }},
+	"nice":    {num: func(r *ProcessRow) float64 { return float64(r.Nice) }, fields: fieldNice},
+	"fds":     {num: func(r *ProcessRow) float64 { return float64(r.FDs) }, fields: fieldFDs},
+	"port": {nums: func(r *ProcessRow) []float64 {
+		out := make([]float64, len(r.Ports))
+		for i, p := range r.Ports {
+			out[i] = float64(p)
+		}
+		return out
+	}, fields: fieldPorts},
+}
+
+// Aliases accepted for convenience.
+func init() {
+	queryFields["cmdline"] = queryFields["cmd"]
+	queryFields["rss"] = queryFields["mem"]
+	queryFields["s"] = queryFields["state"]
+	queryFields["thr"] = queryFields["threads"]
+	queryFields["ni"] = queryFields["nice"]
+	queryFields["listen"] = queryFields["port"]
+}
+
+// query is a parsed filter plus the optional process fields it reads.
+type query struct {
+	root     queryNode
+	fields   procField
+	patterns []patternTerm // bare words, for scoring and highlighting
+}
+
+// Match reports whether r satisfies the query. A nil query matches all.
+func (q *query) Match(r *ProcessRow) bool {
+	if q == nil || q.root == nil {
+		return true
+	}
+	return q.root.match(r)
+}
+
+// parseQuery parses a filter string. An empty string yields a nil query.
+func parseQuery(s string, opts matchOptions) (*query, error) {
+	toks, err := lexQuery(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(toks) == 0 {
+		return nil, nil
+	}
+	p := &queryParser{toks: toks, opts: opts, q: &query{}}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, fmt.Errorf("unexpected %q", p.toks[p.pos].text)
+	}
+	p.q.root = root
+	return p.q, nil
+}
+
+// ---------------------------------------------------------------------------
+// Lexer
+// ---------------------------------------------------------------------------
+
+type tokKind int
+
+const (
+	tokWord tokKind = iota
+	tokLParen
+	tokRParen
+	tokAnd
+	tokOr
+	tokNot
+)
+
+type token struct {
+	kind   tokKind
+	text   string
+	quoted bool // word began with a double quote
+}
+
+// lexQuery splits s into parentheses, boolean operators and terms. A term
+// runs to the next unquoted space or parenthesis; double quotes let values
+// contain either.
+func lexQuery(s string) ([]token, error) {
+	var toks []token
+	rs := []rune(s)
+	for i := 0; i < len(rs); {
+		c := rs[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(':
+			toks = append(toks, token{kind: tokLParen, text: "("})
+			i++
+		case c == ')':
+			toks = append(toks, token{kind: tokRParen, text: ")"})
+			i++
+		case c == '!' && (i+1 == len(rs) || (rs[i+1] != '=' && rs[i+1] != '~')):
+			toks = append(toks, token{kind: tokNot, text: "!"})
+			i++
+		default:
+			var b strings.Builder
+			startsQuoted := c == '"'
+			quoted := false
+			for ; i < len(rs); i++ {
+				c := rs[i]
+				if c == '"' {
+					quoted = !quoted
+					continue
+				}
+				if !quoted && (unicode.IsSpace(c) || c == '(' || c == ')') {
+					break
+				}
+				b.WriteRune(c)
+			}
+			if quoted {
+				return nil, fmt.Errorf("unterminated quote")
+			}
+			w := b.String()
+			if startsQuoted {
+				toks = append(toks, token{kind: tokWord, text: w, quoted: true})
+				continue
+			}
+			switch strings.ToUpper(w) {
+			case "AND", "&&":
+				toks = append(toks, token{kind: tokAnd, text: w})
+			case "OR", "||":
+				toks = append(toks, token{kind: tokOr, text: w})
+			case "NOT":
+				toks = append(toks, token{kind: tokNot, text: w})
+			default:
+				toks = append(toks, token{kind: tokWord, text: w})
+			}
+		}
+	}
+	return toks, nil
+}
+
+// ---------------------------------------------------------------------------
+// Parser
+// ---------------------------------------------------------------------------
+
+type queryParser struct {
+	toks []token
+	pos  int
+	opts matchOptions
+	q    *query
+}
+
+func (p *queryParser) peek() (token, bool) {
+	if p.pos >= len(p.toks) {
+		return token{}, false
+	}
+	return p.toks[p.pos], true
+}
+
+func (p *queryParser) parseOr() (queryNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind != tokOr {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+}
+
+func (p *queryParser) parseAnd() (queryNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind == tokOr || t.kind == tokRParen {
+			return left, nil
+		}
+		if t.kind == tokAnd {
+			p.pos++
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+}
+
+func (p *queryParser) parseNot() (queryNode, error) {
+	t, ok := p.peek()
+	if ok && t.kind == tokNot {
+		p.pos++
+		n, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{n}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *queryParser) parsePrimary() (queryNode, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of filter")
+	}
+	switch t.kind {
+	case tokLParen:
+		p.pos++
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := p.peek(); !ok || t.kind != tokRParen {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return n, nil
+	case tokWord:
+		p.pos++
+		if t.quoted {
+			return p.parsePattern(t.text)
+		}
+		return p.parseTerm(t.text)
+	default:
+		return nil, fmt.Errorf("unexpected %q", t.text)
+	}
+}
+
+// termOps is ordered so two-character operators are tried first.
+var termOps = []string{">=", "<=", "!=", "!~", ":", "=", "~", ">", "<"}
+
+func (p *queryParser) parseTerm(w string) (queryNode, error) {
+	// Split "field<op>value" at the first operator after a field name.
+	end := 0
+	for end < len(w) && (w[end] == '_' || unicode.IsLetter(rune(w[end]))) {
+		end++
+	}
+	op := ""
+	for _, o := range termOps {
+		if strings.HasPrefix(w[end:], o) {
+			op = o
+			break
+		}
+	}
+	if end == 0 || op == "" {
+		return p.parsePattern(w)
+	}
+
+	name := strings.ToLower(w[:end])
+	val := w[end+len(op):]
+	f, ok := queryFields[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q", name)
+	}
+	if val == "" {
+		return nil, fmt.Errorf("missing value after %s%s", name, op)
+	}
+	p.q.fields |= f.fields
+
+	if f.text != nil {
+		switch op {
+		case "<", "<=", ">", ">=":
+			return nil, fmt.Errorf("%s is text; %s needs a number", name, op)
+		case "~", "!~":
+			re, err := regexp.Compile("(?i)" + val)
+			if err != nil {
+				return nil, fmt.Errorf("bad regex for %s: %v", name, err)
+			}
+			return textTerm{get: f.text, op: op, re: re}, nil
+		}
+		return textTerm{get: f.text, op: op, val: strings.ToLower(val)}, nil
+	}
+
+	if op == "~" || op == "!~" {
+		return nil, fmt.Errorf("%s is numeric; use <, >, = instead of %s", name, op)
+	}
+	var n float64
+	var err error
+	if f.memory {
+		n, err = parseSizeMB(val)
+	} else {
+		n, err = strconv.ParseFloat(strings.TrimSuffix(val, "%"), 64)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("bad number for %s: %q", name, val)
+	}
+	if f.nums != nil {
+		return listTerm{get: f.nums, op: op, val: n}, nil
+	}
+	return numTerm{get: f.num, op: op, val: n}, nil
+}
+
+// parsePattern turns a bare word into a patternTerm.
+func (p *queryParser) parsePattern(w string) (queryNode, error) {
+	t, err := newPatternTerm(w, p.opts)
+	if err != nil {
+		return nil, err
+	}
+	if p.opts.cmdline {
+		p.q.fields |= fieldCmdline
+	}
+	p.q.patterns = append(p.q.patterns, t)
+	return t, nil
+}
+
+// parseSizeMB parses "512", "512MB", "1.5G", "800k" into megabytes.
+func parseSizeMB(s string) (float64, error) {
+	u := strings.ToUpper(s)
+	mult := 1.0
+	for _, suf := range []struct {
+		s string
+		m float64
+	}{
+		{"GB", 1024}, {"G", 1024},
+		{"MB", 1}, {"M", 1},
+		{"KB", 1.0 / 1024}, {"K", 1.0 / 1024},
+		{"B", 1.0 / (1 << 20)},
+	} {
+		if strings.HasSuffix(u, suf.s) {
+			u = strings.TrimSuffix(u, suf.s)
+			mult = suf.m
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(u, 64)
+	if err != nil {
+		return 0, err
+	}
+	return n * mult, nil
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+var queryRows = []ProcessRow{
+	{PID: 1, PPID: 0, Name: "systemd", User: "root", CPU: 0.1, MemMB: 12, State: "S", Threads: 1},
+	{PID: 1234, PPID: 1, Name: "postgres", User: "postgres", CPU: 25, MemMB: 600, State: "R", Threads: 8,
+		Ports: []uint32{5432}},
+	{PID: 1300, PPID: 1234, Name: "postgres", User: "postgres", CPU: 2, MemMB: 120, State: "S", Threads: 1},
+	{PID: 2000, PPID: 1, Name: "java", User: "alice", CPU: 80, MemMB: 2048, State: "S", Threads: 64,
+		Cmdline: "/usr/bin/java -jar app.jar", Ports: []uint32{8080, 8443}},
+	{PID: 2100, PPID: 2000, Name: "javac", User: "alice", CPU: 0, MemMB: 0.5, State: "Z", Nice: 10},
+	{PID: 3000, PPID: 1, Name: "Chrome Helper", User: "bob", CPU: 5, MemMB: 300, State: "S"},
+}
+
+// matchPIDs runs q over queryRows and returns the PIDs that matched.
+func matchPIDs(t *testing.T, q string) []int32 {
+	t.Helper()
+	parsed, err := parseQuery(q, matchOptions{})
+	if err != nil {
+		t.Fatalf("parseQuery(%q): %v", q, err)
+	}
+	var out []int32
+	for i := range queryRows {
+		if parsed.Match(&queryRows[i]) {
+			out = append(out, queryRows[i].PID)
+		}
+	}
+	return out
+}
+
+func TestQueryMatch(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []int32
+	}{
+		{"", []int32{1, 1234, 1300, 2000, 2100, 3000}},
+		{"   ", []int32{1, 1234, 1300, 2000, 2100, 3000}},
+
+		// Bare words keep the old case-insensitive name substring behaviour.
+		{"java", []int32{2000, 2100}},
+		{"CHROME", []int32{3000}},
+
+		{"user:postgres", []int32{1234, 1300}},
+		{"user=alice", []int32{2000, 2100}},
+		{"user!=root", []int32{1234, 1300, 2000, 2100, 3000}},
+		{"pid:1234", []int32{1234}},
+		{"ppid=1", []int32{1234, 2000, 3000}},
+		{"cpu>20", []int32{1234, 2000}},
+		{"cpu>=25", []int32{1234, 2000}},
+		{"cpu<1", []int32{1, 2100}},
+		{"cpu<=2%", []int32{1, 1300, 2100}},
+		{"mem>=500MB", []int32{1234, 2000}},
+		{"mem>1G", []int32{2000}},
+		{"mem<1k", nil},
+		{"mem<=512KB", []int32{2100}},
+		{"threads>4", []int32{1234, 2000}},
+		{"nice:10", []int32{2100}},
+		{"state:Z", []int32{2100}},
+		{"state:z", []int32{2100}},
+		{"name~^java", []int32{2000, 2100}},
+		{"name~^java$", []int32{2000}},
+		{"name!~^java", []int32{1, 1234, 1300, 3000}},
+		{"cmd:app.jar", []int32{2000}},
+		{`name:"chrome helper"`, []int32{3000}},
+		{`name~"^(java|systemd)$"`, []int32{1, 2000}},
+		{"port:5432", []int32{1234}},
+		{"port=8443", []int32{2000}},
+		{"listen:8080", []int32{2000}},
+		{"port>8000", []int32{2000}},
+		{"port:22", nil},
+		{"port!=8080", []int32{1, 1234, 1300, 2100, 3000}},
+
+		// Boolean operators and precedence: NOT > AND > OR.
+		{"user:postgres cpu>20", []int32{1234}},
+		{"user:postgres AND cpu>20", []int32{1234}},
+		{"user:postgres && cpu>20", []int32{1234}},
+		{"user:bob OR state:Z", []int32{2100, 3000}},
+		{"user:bob || state:Z", []int32{2100, 3000}},
+		{"NOT user:root", []int32{1234, 1300, 2000, 2100, 3000}},
+		{"not user:root and not user:postgres", []int32{2000, 2100, 3000}},
+		{"!state:S", []int32{1234, 2100}},
+		{"user:bob OR user:alice cpu>50", []int32{2000, 3000}},
+		{"(user:bob OR user:alice) cpu>1", []int32{2000, 3000}},
+		{"NOT (user:alice OR user:postgres)", []int32{1, 3000}},
+		{"((pid:1))", []int32{1}},
+	}
+	for _, tt := range tests {
+		got := matchPIDs(t, tt.query)
+		if !equalPIDs(got, tt.want) {
+			t.Errorf("%q matched %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestQueryParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string // substring of the error
+	}{
+		{"bogus:1", "unknown field"},
+		{"cpu>", "missing value"},
+		{"cpu>abc", "bad number"},
+		{"mem>12XB", "bad number"},
+		{"user>5", "is text"},
+		{"pid~12", "is numeric"},
+		{"port:http", "bad number"},
+		{`name~"("`, "bad regex"},
+		{"(user:root", "missing )"},
+		{"user:root)", "unexpected"},
+		{"user:root AND", "unexpected end"},
+		{"OR user:root", "unexpected"},
+		{"NOT", "unexpected end"},
+		{`name:"chrome`, "unterminated quote"},
+	}
+	for _, tt := range tests {
+		_, err := parseQuery(tt.query, matchOptions{})
+		if err == nil {
+			t.Errorf("%q: expected error containing %q", tt.query, tt.want)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: error %q, want it to contain %q", tt.query, err, tt.want)
+		}
+	}
+}
+
+func TestQueryFields(t *testing.T) {
+	tests := []struct {
+		query string
+		want  procField
+	}{
+		{"java cpu>1 user:root", fieldNone},
+		{"state:Z", fieldState},
+		{"cmd:foo OR nice>0", fieldCmdline | fieldNice},
+		{"NOT (fds>100 tty:pts)", fieldFDs | fieldTTY},
+		{"port:80", fieldPorts},
+	}
+	for _, tt := range tests {
+		q, err := parseQuery(tt.query, matchOptions{})
+		if err != nil {
+			t.Fatalf("parseQuery(%q): %v", tt.query, err)
+		}
+		if q.fields != tt.want {
+			t.Errorf("%q: fields %b, want %b", tt.query, q.fields, tt.want)
+		}
+	}
+}
+
+func TestParseSizeMB(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+	}{
+		{"500", 500},
+		{"500MB", 500},
+		{"500m", 500},
+		{"1.5G", 1536},
+		{"2gb", 2048},
+		{"1024K", 1},
+		{"1048576B", 1},
+	}
+	for _, tt := range tests {
+		got, err := parseSizeMB(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("parseSizeMB(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestSetFilterKeepsLastValidQuery(t *testing.T) {
+	m := NewModel(&fakeCollector{})
+	m.allProcs = queryRows
+
+	if err := m.setFilter("user:postgres"); err != nil {
+		t.Fatal(err)
+	}
+	m.applyFilterAndSort()
+	if len(m.visibleProc) != 2 {
+		t.Fatalf("got %d rows, want 2", len(m.visibleProc))
+	}
+
+	// Mid-typing a comparison: the error is reported but the table keeps
+	// the previous result.
+	if err := m.setFilter("user:postgres cpu>"); err == nil {
+		t.Fatal("expected parse error")
+	}
+	m.applyFilterAndSort()
+	if m.filterErr == nil || len(m.visibleProc) != 2 {
+		t.Fatalf("filterErr=%v rows=%d; want error and 2 rows", m.filterErr, len(m.visibleProc))
+	}
+
+	m.setFilter("user:postgres cpu>20")
+	m.applyFilterAndSort()
+	if m.filterErr != nil || len(m.visibleProc) != 1 || m.visibleProc[0].PID != 1234 {
+		t.Fatalf("filterErr=%v rows=%v; want PID 1234 only", m.filterErr, m.visibleProc)
+	}
+}
+
+func equalPIDs(a, b []int32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestQueryMatchModes(t *testing.T) {
+	tests := []struct {
+		query string
+		opts  matchOptions
+		want  []int32
+	}{
+		{"^java$", matchOptions{mode: matchRegex}, []int32{2000}},
+		{"post|sys", matchOptions{mode: matchRegex}, []int32{1, 1234, 1300}},
+		{"jv", matchOptions{mode: matchFuzzy}, []int32{2000, 2100}},
+		{"chlp", matchOptions{mode: matchFuzzy}, []int32{3000}},
+		{"jv user:alice cpu>1", matchOptions{mode: matchFuzzy}, []int32{2000}},
+		{"app.jar", matchOptions{cmdline: true}, []int32{2000}},
+		{`"-jar"`, matchOptions{cmdline: true}, []int32{2000}},
+		{`"a:b"`, matchOptions{}, nil},
+	}
+	for _, tt := range tests {
+		q, err := parseQuery(tt.query, tt.opts)
+		if err != nil {
+			t.Fatalf("parseQuery(%q): %v", tt.query, err)
+		}
+		var got []int32
+		for i := range queryRows {
+			if q.Match(&queryRows[i]) {
+				got = append(got, queryRows[i].PID)
+			}
+		}
+		if !equalPIDs(got, tt.want) {
+			t.Errorf("%q (%s) matched %v, want %v", tt.query, tt.opts.mode, got, tt.want)
+		}
+	}
+
+	if _, err := parseQuery("(", matchOptions{mode: matchRegex}); err == nil {
+		t.Error("unbalanced parenthesis should still be a parse error in regex mode")
+	}
+	if _, err := parseQuery(`"a["`, matchOptions{mode: matchRegex}); err == nil || !strings.Contains(err.Error(), "bad regex") {
+		t.Errorf("quoted bad regex: got %v", err)
+	}
+}
+
+func TestCmdNamePositions(t *testing.T) {
+	tests := []struct {
+		pos           []int
+		cmdline, name string
+		want          []int
+	}{
+		// "java" in "/usr/bin/java -jar app.jar" is runes 9–12.
+		{[]int{9, 10, 11, 12}, "/usr/bin/java -jar app.jar", "java", []int{0, 1, 2, 3}},
+		{[]int{4, 9, 19}, "/usr/bin/java -jar app.jar", "java", []int{0}},
+		{[]int{0, 1}, "nginx: worker process", "nginx", []int{0, 1}},
+		{[]int{11, 12}, `C:\Windows\notepad.exe x`, "notepad.exe", []int{0, 1}},
+		{[]int{17, 18}, `C:\Program Files\app.exe`, "app.exe", nil}, // space in the path
+		{[]int{0}, "", "kworker/0:1", nil},
+		{[]int{0, 3}, "bash -c x", "sh", nil},
+	}
+	for _, tt := range tests {
+		if got := cmdNamePositions(tt.pos, tt.cmdline, tt.name); !equalInts(got, tt.want) {
+			t.Errorf("cmdNamePositions(%v, %q, %q) = %v, want %v", tt.pos, tt.cmdline, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	score := func(pat, text string) int {
+		s, _, ok := fuzzyMatch(lowerRunes(pat), []rune(text))
+		if !ok {
+			t.Fatalf("%q should fuzzy-match %q", pat, text)
+		}
+		return s
+	}
+	if _, _, ok := fuzzyMatch(lowerRunes("xyz"), []rune("java")); ok {
+		t.Error("xyz should not match java")
+	}
+	// Word starts and consecutive runs beat scattered characters.
+	if a, b := score("gc", "go-cache"), score("gc", "bigcat"); a <= b {
+		t.Errorf("go-cache (%d) should outrank bigcat (%d)", a, b)
+	}
+	if a, b := score("java", "java"), score("java", "jxaxvxa"); a <= b {
+		t.Errorf("exact (%d) should outrank scattered (%d)", a, b)
+	}
+	if a, b := score("ka", "KafkaServer"), score("ka", "xkxa"); a <= b {
+		t.Errorf("prefix (%d) should outrank gapped (%d)", a, b)
+	}
+	// The window is tightened: "gc" in "g-gc" uses the second g.
+	if _, pos, _ := fuzzyMatch(lowerRunes("gc"), []rune("g-gc")); !equalInts(pos, []int{2, 3}) {
+		t.Errorf("positions %v, want [2 3]", pos)
+	}
+}
+
+func TestFuzzySortsByScore(t *testing.T) {
+	m := NewModel(&fakeCollector{})
+	m.allProcs = []ProcessRow{
+		{PID: 1, Name: "xjxaxvxa", CPU: 90},
+		{PID: 2, Name: "java", CPU: 1},
+		{PID: 3, Name: "javac", CPU: 50},
+	}
+	m.filterMode = matchFuzzy
+	m.setFilter("java")
+	m.applyFilterAndSort()
+	var got []int32
+	for _, r := range m.visibleProc {
+		got = append(got, r.PID)
+	}
+	// Equal scores (java, javac) fall back to CPU descending.
+	if !equalPIDs(got, []int32{3, 2, 1}) {
+		t.Errorf("order %v, want [3 2 1]", got)
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// A recording is a gzip stream of JSON lines. Each session written by
+// --record starts with a header line and then appends one entry per sample:
+// a sysStatsMsg or a process list, stamped with the time it was taken.
+// Sessions appended to an existing file become further gzip members, which
+// gzip readers treat as one continuous stream.
+//
+// The stream is flushed after every entry, so a recording cut short by a
+// crash or SIGKILL is still readable up to its last sample, and so are the
+// sessions appended after it.
+
+// recordVersion is written in each session header. Readers reject newer
+// versions rather than misread them.
+const recordVersion = 1
+
+// recordFields are the optional process fields --record-all adds to every
+// sample, so a replay can show any column, filter on any field and fill the
+// detail pane regardless of what the recording session displayed. Without
+// it a sample holds what the session read anyway: the fields of its
+// columns, sort key, filter and alert rules.
+const recordFields = fieldCmdline | fieldState | fieldNice | fieldShared | fieldTimes |
+	fieldStart | fieldFDs | fieldIO | fieldGroup | fieldTTY | fieldPorts
+
+type recordEntry struct {
+	Version int          `json:"gomon_recording,omitempty"` // session header only
+	Time    time.Time    `json:"t"`
+	Sys     *sysStatsMsg `json:"sys,omitempty"`
+	Procs   []ProcessRow `json:"procs,omitempty"`
+}
+
+// recorder appends entries to a recording. It is shared by the concurrent
+// sysStats and processes fetches, so writes are serialized. The first write
+// error is kept and reported by Close; later samples are dropped.
+type recorder struct {
+	mu  sync.Mutex
+	f   *os.File
+	gz  *gzip.Writer
+	enc *json.Encoder
+	err error
+}
+
+func openRecorder(path string) (*recorder, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	gz := gzip.NewWriter(f)
+	r := &recorder{f: f, gz: gz, enc: json.NewEncoder(gz)}
+	r.write(recordEntry{Version: recordVersion, Time: time.Now()})
+	if r.err != nil {
+		f.Close()
+		return nil, r.err
+	}
+	return r, nil
+}
+
+func (r *recorder) write(e recordEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	if err := r.enc.Encode(e); err != nil {
+		r.err = err
+		return
+	}
+	r.err = r.gz.Flush()
+}
+
+// Close finishes the gzip member and closes the file.
+func (r *recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	err := r.err
+	if cerr := r.gz.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := r.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// recordingCollector passes samples through from another collector and
+// appends each one to a recording. Failed samples are not recorded.
+type recordingCollector struct {
+	Collector
+	rec *recorder
+	all bool // also collect and record recordFields
+}
+
+func (c recordingCollector) SysStats() sysStatsMsg {
+	s := c.Collector.SysStats()
+	if s.Err == nil {
+		c.rec.write(recordEntry{Time: time.Now(), Sys: &s})
+	}
+	return s
+}
+
+func (c recordingCollector) Processes(fields procField) processesMsg {
+	if c.all {
+		fields |= recordFields
+	}
+	p := c.Collector.Processes(fields)
+	if p.Err == nil {
+		c.rec.write(recordEntry{Time: time.Now(), Procs: p.Procs})
+	}
+	return p
+}
+
+// replayFrame is one process sample and the system sample taken with it.
+type replayFrame struct {
+	Time  time.Time
+	Sys   sysStatsMsg
+	Procs []ProcessRow
+}
+
+// readRecording loads every frame of a recording into memory, in time
+// order. A session that ends mid-entry (the recorder was killed) is read up
+// to its last complete sample; see readSession for what follows it.
+func readRecording(path string) ([]replayFrame, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rr recordingReader
+	for off := 0; off < len(data); {
+		size, err := rr.readSession(data[off:])
+		switch {
+		case errors.Is(err, errNotRecording) && off == 0:
+			return nil, fmt.Errorf("%s: not a gomon recording", path)
+		case err != nil && !errors.Is(err, errNotRecording):
+			return nil, fmt.Errorf("%s: %v", path, err)
+		case size > 0:
+			off += size
+			continue
+		}
+		// The session at off was cut short, so its gzip member has no end
+		// and the next session's member follows its last flushed block.
+		// Resume at the next gzip header.
+		next := bytes.Index(data[off+1:], gzipMagic)
+		if next < 0 {
+			break
+		}
+		off += 1 + next
+	}
+	if len(rr.frames) == 0 {
+		return nil, fmt.Errorf("%s: recording has no samples", path)
+	}
+	sort.SliceStable(rr.frames, func(i, j int) bool { return rr.frames[i].Time.Before(rr.frames[j].Time) })
+	return rr.frames, nil
+}
+
+// gzipMagic starts every gzip member: ID1, ID2 and CM (deflate).
+var gzipMagic = []byte{0x1f, 0x8b, 8}
+
+// errNotRecording is returned by readSession for data that doesn't start
+// with a session.
+var errNotRecording = errors.New("not a gomon recording")
+
+// recordingReader collects the frames of a recording's sessions.
+type recordingReader struct {
+	frames []replayFrame
+	sys    *sysStatsMsg // latest system sample
+}
+
+// readSession reads the session whose gzip member starts data. It returns
+// the member's size, or 0 if the member has no end because the recorder
+// was killed; the samples before that point are kept.
+func (rr *recordingReader) readSession(data []byte) (int, error) {
+	r := bytes.NewReader(data) // an io.ByteReader, so gzip reads no further than the member
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, errNotRecording
+	}
+	gz.Multistream(false)
+	dec := json.NewDecoder(gz)
+	for entries := 0; ; entries++ {
+		var e recordEntry
+		err := dec.Decode(&e)
+		switch {
+		case err == io.EOF:
+			return len(data) - r.Len(), nil
+		case err != nil && entries == 0, err == nil && entries == 0 && e.Version == 0:
+			return 0, errNotRecording
+		case err != nil:
+			return 0, nil
+		case e.Version > recordVersion:
+			return 0, fmt.Errorf("recording format %d is newer than this gomon supports (%d)",
+				e.Version, recordVersion)
+		}
+		rr.add(e)
+	}
+}
+
+func (rr *recordingReader) add(e recordEntry) {
+	switch {
+	case e.Sys != nil:
+		if rr.sys == nil {
+			// Backfill frames sampled before the first system sample.
+			for i := range rr.frames {
+				rr.frames[i].Sys = *e.Sys
+			}
+		}
+		rr.sys = e.Sys
+	case e.Procs != nil:
+		fr := replayFrame{Time: e.Time, Procs: e.Procs}
+		if rr.sys != nil {
+			fr.Sys = *rr.sys
+		}
+		rr.frames = append(rr.frames, fr)
+	}
+}
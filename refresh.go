@@ -0,0 +1,84 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// refreshSteps are the intervals the faster/slower keys move between.
+var refreshSteps = []time.Duration{
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2 * time.Second,
+	3 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// setRefresh moves to the next shorter (dir < 0) or longer (dir > 0) step.
+// A new tick chain starts so the change takes effect immediately rather
+// than after the old, possibly long, interval.
+func (m *Model) setRefresh(dir int) tea.Cmd {
+	next := m.refresh
+	if dir < 0 {
+		for i := len(refreshSteps) - 1; i >= 0; i-- {
+			if refreshSteps[i] < m.refresh {
+				next = refreshSteps[i]
+				break
+			}
+		}
+	} else {
+		for _, d := range refreshSteps {
+			if d > m.refresh {
+				next = d
+				break
+			}
+		}
+	}
+	if next == m.refresh {
+		return nil
+	}
+	m.refresh = next
+	if m.paused {
+		return nil
+	}
+	m.tickGen++
+	return tickCmd(m.refresh, m.tickGen)
+}
+
+// togglePause freezes or resumes the snapshot. While paused no ticks are
+// scheduled and late replies from in-flight fetches are dropped, so the
+// table only changes when the user sorts, filters or steps.
+func (m *Model) togglePause() tea.Cmd {
+	m.paused = !m.paused
+	m.tickGen++
+	m.fetchGen++
+	if m.paused {
+		return nil
+	}
+	return tea.Batch(tickCmd(m.refresh, m.tickGen), m.fetchSysStats(), m.fetchProcesses())
+}
+
+// step takes exactly one new sample while paused. CPU% and I/O rates are
+// averaged over the time since the previous sample.
+func (m *Model) step() tea.Cmd {
+	if !m.paused {
+		return nil
+	}
+	if m.replay != nil && !m.replay.stepFrame() {
+		m.statusMsg = "end of recording"
+		return nil
+	}
+	m.fetchGen++
+	return tea.Batch(m.fetchSysStats(), m.fetchProcesses())
+}
+
+// acceptSample reports whether a sysStats/processes reply requested in
+// fetch generation gen should be applied: always when running, and when
+// paused only if it was requested after the pause or the latest step, so a
+// reply still in flight from before can't stand in for the step's own.
+func (m *Model) acceptSample(gen int) bool {
+	return !m.paused || gen == m.fetchGen
+}
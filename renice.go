@@ -0,0 +1,208 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// Nice values accepted by the renice prompt.
+const (
+	minNice = -20
+	maxNice = 19
+)
+
+// reniceResult is the outcome for one PID of a renice action.
+type reniceResult struct {
+	PID int32
+	Err error
+}
+
+// reniceResultMsg is returned once every target has been reniced.
+type reniceResultMsg struct {
+	Nice    int
+	Results []reniceResult
+}
+
+// niceValuesMsg carries the current nice value of each renice target, read
+// when the prompt opens (the NI column may not be collected).
+type niceValuesMsg map[int32]int32
+
+// ---------------------------------------------------------------------------
+// Prompt
+// ---------------------------------------------------------------------------
+
+// openRenice shows the prompt for the action targets.
+func (m *Model) openRenice() tea.Cmd {
+	targets := m.actionTargets()
+	if len(targets) == 0 {
+		return nil
+	}
+	ti := textinput.New()
+	ti.Placeholder = fmt.Sprintf("%d..%d", minNice, maxNice)
+	ti.CharLimit = 3
+	ti.Width = 6
+	ti.Focus()
+
+	m.reniceTargets = targets
+	m.reniceInput = ti
+	m.reniceCur = nil
+	m.reniceErr = ""
+	m.mode = ModeRenice
+	return tea.Batch(textinput.Blink, fetchNiceValues(targets))
+}
+
+func (m Model) handleReniceKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case keyEsc:
+		m.mode = ModeNormal
+		m.reniceTargets = nil
+		return m, nil
+
+	case keyEnter:
+		n, err := strconv.Atoi(strings.TrimSpace(m.reniceInput.Value()))
+		if err != nil || n < minNice || n > maxNice {
+			m.reniceErr = fmt.Sprintf("enter a whole number from %d to %d", minNice, maxNice)
+			return m, nil
+		}
+		return m, reniceProcesses(m.reniceTargets, n)
+	}
+
+	var cmd tea.Cmd
+	m.reniceInput, cmd = m.reniceInput.Update(msg)
+	m.reniceErr = ""
+	return m, cmd
+}
+
+// ---------------------------------------------------------------------------
+// Commands
+// ---------------------------------------------------------------------------
+
+func fetchNiceValues(targets []ProcessRow) tea.Cmd {
+	pids := make([]int32, len(targets))
+	for i, t := range targets {
+		pids[i] = t.PID
+	}
+	return func() tea.Msg {
+		out := make(niceValuesMsg, len(pids))
+		for _, pid := range pids {
+			p, err := process.NewProcess(pid)
+			if err != nil {
+				continue
+			}
+			if n, err := processNice(p); err == nil {
+				out[pid] = n
+			}
+		}
+		return out
+	}
+}
+
+// reniceProcesses sets the nice value of every target.
+func reniceProcesses(targets []ProcessRow, nice int) tea.Cmd {
+	pids := make([]int32, len(targets))
+	for i, t := range targets {
+		pids[i] = t.PID
+	}
+	return func() tea.Msg {
+		results := make([]reniceResult, len(pids))
+		for i, pid := range pids {
+			results[i] = reniceResult{PID: pid, Err: setNice(pid, nice)}
+		}
+		return reniceResultMsg{Nice: nice, Results: results}
+	}
+}
+
+// summarizeRenice condenses a renice result into one status-bar line,
+// spelling out permission errors since they are the common failure.
+func summarizeRenice(msg reniceResultMsg) string {
+	var failed []reniceResult
+	for _, r := range msg.Results {
+		if r.Err != nil {
+			failed = append(failed, r)
+		}
+	}
+	if len(msg.Results) == 1 {
+		r := msg.Results[0]
+		if r.Err != nil {
+			return fmt.Sprintf("renice PID %d to %d failed: %s", r.PID, msg.Nice, reniceErrText(r.Err))
+		}
+		return fmt.Sprintf("reniced PID %d to %d", r.PID, msg.Nice)
+	}
+	s := fmt.Sprintf("reniced %d processes to %d: %d ok, %d failed",
+		len(msg.Results), msg.Nice, len(msg.Results)-len(failed), len(failed))
+	if len(failed) > 0 {
+		s += fmt.Sprintf(" (PID %d: %s)", failed[0].PID, reniceErrText(failed[0].Err))
+	}
+	return s
+}
+
+func reniceErrText(err error) string {
+	if errors.Is(err, os.ErrPermission) {
+		return "permission denied — " + reniceDeniedHint
+	}
+	return err.Error()
+}
+
+// ---------------------------------------------------------------------------
+// View
+// ---------------------------------------------------------------------------
+
+func (m *Model) renderReniceOverlay(base string) string {
+	cur := func(pid int32) string {
+		if m.reniceCur == nil {
+			return "…"
+		}
+		if n, ok := m.reniceCur[pid]; ok {
+			return strconv.Itoa(int(n))
+		}
+		return "?"
+	}
+
+	var target string
+	if len(m.reniceTargets) == 1 {
+		t := m.reniceTargets[0]
+		target = fmt.Sprintf("  PID %d (%s)\n", t.PID, t.Name) +
+			fmt.Sprintf("  owned by: %s   current nice: %s", t.User, cur(t.PID))
+	} else {
+		var b strings.Builder
+		fmt.Fprintf(&b, "  %d marked processes:\n", len(m.reniceTargets))
+		for i, t := range m.reniceTargets {
+			if i == 8 {
+				b.WriteString(styleOverlayHint.Render(
+					fmt.Sprintf("    …and %d more", len(m.reniceTargets)-8)))
+				b.WriteString("\n")
+				break
+			}
+			fmt.Fprintf(&b, "    %7d  %-24s  nice %s\n", t.PID, truncate(t.Name, 24), cur(t.PID))
+		}
+		target = strings.TrimSuffix(b.String(), "\n")
+	}
+
+	prompt := "  New nice value: " + m.reniceInput.View()
+	if m.reniceErr != "" {
+		prompt += "\n  " + styleStatusError.Render(m.reniceErr)
+	}
+
+	content := styleOverlayTitle.Render("Renice") + "\n\n" +
+		target + "\n\n" +
+		prompt + "\n\n" +
+		"  " + styleOverlayHint.Render(fmt.Sprintf("%d highest priority … %d lowest · Enter apply · Esc cancel", minNice, maxNice))
+
+	box := styleOverlayBorder.Render(content)
+
+	return lipgloss.Place(
+		m.termWidth, m.termHeight,
+		lipgloss.Center, lipgloss.Center,
+		box,
+		lipgloss.WithWhitespaceChars(" "),
+		lipgloss.WithWhitespaceForeground(lipgloss.Color("0")),
+	)
+}
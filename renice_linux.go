@@ -0,0 +1,38 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+)
+
+const reniceDeniedHint = "raising priority needs root or CAP_SYS_NICE"
+
+// setNice renices every thread of pid. On Linux setpriority(2) with
+// PRIO_PROCESS only affects the one thread whose TID is given, so a
+// multi-threaded process would otherwise keep most of its threads at the
+// old priority.
+func setNice(pid int32, nice int) error {
+	tids, err := filepath.Glob(filepath.Join("/proc", strconv.Itoa(int(pid)), "task", "*"))
+	if err != nil || len(tids) == 0 {
+		return setpriority(int(pid), nice)
+	}
+	var first error
+	for _, dir := range tids {
+		tid, err := strconv.Atoi(filepath.Base(dir))
+		if err != nil {
+			continue
+		}
+		// A thread may exit between the glob and the call.
+		if err := setpriority(tid, nice); err != nil && !errors.Is(err, syscall.ESRCH) && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+func setpriority(id, nice int) error {
+	return os.NewSyscallError("setpriority", syscall.Setpriority(syscall.PRIO_PROCESS, id, nice))
+}
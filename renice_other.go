@@ -0,0 +1,15 @@
+//go:build !linux && !windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+const reniceDeniedHint = "raising priority needs root"
+
+// setNice sets the nice value of pid.
+func setNice(pid int32, nice int) error {
+	return os.NewSyscallError("setpriority", syscall.Setpriority(syscall.PRIO_PROCESS, int(pid), nice))
+}
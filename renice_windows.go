@@ -0,0 +1,32 @@
+//go:build windows
+
+package main
+
+import "golang.org/x/sys/windows"
+
+const reniceDeniedHint = "run gomon as Administrator"
+
+// setNice maps a nice value onto the nearest Windows priority class, which
+// is the only per-process priority Windows exposes.
+func setNice(pid int32, nice int) error {
+	h, err := windows.OpenProcess(windows.PROCESS_SET_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return err
+	}
+	defer windows.CloseHandle(h)
+
+	var class uint32
+	switch {
+	case nice <= -15:
+		class = windows.HIGH_PRIORITY_CLASS
+	case nice < 0:
+		class = windows.ABOVE_NORMAL_PRIORITY_CLASS
+	case nice == 0:
+		class = windows.NORMAL_PRIORITY_CLASS
+	case nice < 10:
+		class = windows.BELOW_NORMAL_PRIORITY_CLASS
+	default:
+		class = windows.IDLE_PRIORITY_CLASS
+	}
+	return windows.SetPriorityClass(h, class)
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// replaySpeeds are the playback rates the faster/slower keys move between
+// while replaying.
+var replaySpeeds = []float64{0.25, 0.5, 1, 2, 4, 8, 16, 32, 64}
+
+const (
+	replaySeek    = 10 * time.Second
+	replaySeekFar = time.Minute
+)
+
+// replayCollector serves the frames of a recording. The model moves a
+// playback clock through them; SysStats and Processes return the latest
+// frame at or before the clock.
+type replayCollector struct {
+	mu     sync.Mutex
+	frames []replayFrame
+	pos    int       // current frame
+	clock  time.Time // playback position in recorded time
+	speed  int       // index into replaySpeeds
+}
+
+func loadRecording(path string) (*replayCollector, error) {
+	frames, err := readRecording(path)
+	if err != nil {
+		return nil, err
+	}
+	return &replayCollector{frames: frames, clock: frames[0].Time, speed: 2}, nil
+}
+
+func (r *replayCollector) SysStats() sysStatsMsg {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.frames[r.pos].Sys
+}
+
+func (r *replayCollector) Processes(fields procField) processesMsg {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return processesMsg{Procs: append([]ProcessRow(nil), r.frames[r.pos].Procs...)}
+}
+
+// Detail builds the detail pane from the recorded row. What a recording
+// doesn't hold (executable, cwd, limits, environment, I/O totals) is
+// marked missing.
+func (r *replayCollector) Detail(pid int32) processDetailMsg {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var row, parent *ProcessRow
+	procs := r.frames[r.pos].Procs
+	for i := range procs {
+		if procs[i].PID == pid {
+			row = &procs[i]
+		}
+	}
+	if row == nil {
+		return processDetailMsg{PID: pid, Err: errors.New("process not in this frame of the recording")}
+	}
+	for i := range procs {
+		if procs[i].PID == row.PPID {
+			parent = &procs[i]
+		}
+	}
+	d := ProcessDetail{
+		PID:       row.PID,
+		PPID:      row.PPID,
+		Name:      row.Name,
+		User:      row.User,
+		Cmdline:   row.Cmdline,
+		StartTime: row.Started,
+		Nice:      row.Nice,
+		Status:    recordedStatus[row.State],
+		NumFDs:    row.FDs,
+		ReadRate:  row.ReadBps,
+		WriteRate: row.WriteBps,
+		missing:   map[string]bool{"exe": true, "cwd": true, "rlimits": true, "environ": true, "io": true},
+	}
+	if parent != nil {
+		d.ParentName = parent.Name
+	}
+	return processDetailMsg{PID: pid, Detail: &d}
+}
+
+// recordedStatus maps a recorded state letter back to gopsutil's status
+// name, which is what the detail pane shows for a live process.
+var recordedStatus = map[string]string{
+	"R": process.Running,
+	"S": process.Sleep,
+	"D": process.Blocked,
+	"T": process.Stop,
+	"Z": process.Zombie,
+	"I": process.Idle,
+	"W": process.Wait,
+	"L": process.Lock,
+}
+
+// advance moves the clock on by d of wall time at the playback speed and
+// reports whether that reached a new frame.
+func (r *replayCollector) advance(d time.Duration) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clock = r.clock.Add(time.Duration(float64(d) * replaySpeeds[r.speed]))
+	return r.sync()
+}
+
+// seek moves the clock by d (negative for back), within the recording.
+func (r *replayCollector) seek(d time.Duration) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clock = r.clock.Add(d)
+	if first := r.frames[0].Time; r.clock.Before(first) {
+		r.clock = first
+	}
+	return r.sync()
+}
+
+// stepFrame moves to the next frame regardless of the time between them.
+func (r *replayCollector) stepFrame() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.pos == len(r.frames)-1 {
+		return false
+	}
+	r.pos++
+	r.clock = r.frames[r.pos].Time
+	return true
+}
+
+// sync points pos at the last frame at or before the clock, clamping the
+// clock to the end of the recording. Must be called with mu held.
+func (r *replayCollector) sync() bool {
+	last := len(r.frames) - 1
+	if end := r.frames[last].Time; r.clock.After(end) {
+		r.clock = end
+	}
+	pos := 0
+	for pos < last && !r.frames[pos+1].Time.After(r.clock) {
+		pos++
+	}
+	moved := pos != r.pos
+	r.pos = pos
+	return moved
+}
+
+// frameTime is when the current frame was recorded.
+func (r *replayCollector) frameTime() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.frames[r.pos].Time
+}
+
+func (r *replayCollector) atEnd() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.pos == len(r.frames)-1
+}
+
+// setSpeed moves to the next slower (dir < 0) or faster (dir > 0) rate.
+func (r *replayCollector) setSpeed(dir int) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.speed += dir
+	if r.speed < 0 {
+		r.speed = 0
+	}
+	if r.speed >= len(replaySpeeds) {
+		r.speed = len(replaySpeeds) - 1
+	}
+	return replaySpeeds[r.speed]
+}
+
+// status describes the playback position for the header.
+func (r *replayCollector) status() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return fmt.Sprintf("%s  %gx  %d/%d", r.frames[r.pos].Time.Format("2006-01-02 15:04:05"),
+		replaySpeeds[r.speed], r.pos+1, len(r.frames))
+}
+
+// ---------------------------------------------------------------------------
+// Model glue
+// ---------------------------------------------------------------------------
+
+// isLiveAction reports whether a normal-mode key acts on or inspects the
+// running system, which a replay can't do: signals, renice, and the socket
+// and file views (neither is recorded).
+func isLiveAction(key string) bool {
+	switch key {
+	case keyDel, keyKill, keyKillTree, keyRenice, keySuspend, keyResume, keyConns, keyFiles:
+		return true
+	}
+	return false
+}
+
+// replayTick advances playback by one refresh interval. Nothing is fetched
+// unless a new frame was reached; at the last frame playback pauses.
+func (m *Model) replayTick() tea.Cmd {
+	moved := m.replay.advance(m.refresh)
+	var cmds []tea.Cmd
+	if m.replay.atEnd() {
+		m.paused = true
+		m.tickGen++
+		m.fetchGen++
+		m.statusMsg = "end of recording"
+	} else {
+		cmds = append(cmds, tickCmd(m.refresh, m.tickGen))
+	}
+	if moved {
+		cmds = append(cmds, m.fetchSysStats(), m.fetchProcesses())
+		if m.mode == ModeDetail {
+			cmds = append(cmds, m.detailCmd(m.detailPID))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// seekReplay jumps by d and shows the frame there. The sparkline history
+// and alert state are cleared: they would otherwise splice samples from
+// both sides of the jump.
+func (m *Model) seekReplay(d time.Duration) tea.Cmd {
+	if !m.replay.seek(d) {
+		return nil
+	}
+	m.procHist = map[int32]*procHistory{}
+	m.sysCPUHist = newRing(historyLen)
+	m.sysMemHist = newRing(historyLen)
+	if m.alerts != nil {
+		m.alerts.reset()
+	}
+	m.fetchGen++
+	cmds := []tea.Cmd{m.fetchSysStats(), m.fetchProcesses()}
+	if m.mode == ModeDetail {
+		cmds = append(cmds, m.detailCmd(m.detailPID))
+	}
+	return tea.Batch(cmds...)
+}
+
+// handleReplayKey handles the keys whose meaning changes while replaying.
+// It reports false for keys it leaves to the normal handler.
+func (m *Model) handleReplayKey(key string) (tea.Cmd, bool) {
+	switch key {
+	case keyFaster, keySlower:
+		dir := 1
+		if key == keySlower {
+			dir = -1
+		}
+		m.statusMsg = fmt.Sprintf("playback %gx", m.replay.setSpeed(dir))
+	case keySeekBack:
+		return m.seekReplay(-replaySeek), true
+	case keySeekFwd:
+		return m.seekReplay(replaySeek), true
+	case keySeekBackFar:
+		return m.seekReplay(-replaySeekFar), true
+	case keySeekFwdFar:
+		return m.seekReplay(replaySeekFar), true
+	default:
+		if isLiveAction(key) {
+			m.statusMsg = "not available when replaying a recording"
+			return nil, true
+		}
+		return nil, false
+	}
+	return nil, true
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// ---------------------------------------------------------------------------
+// Marking
+// ---------------------------------------------------------------------------
+
+// toggleMark flips the mark on the selected row and advances the cursor,
+// so holding Space marks a run of rows.
+func (m *Model) toggleMark() {
+	if len(m.visibleProc) == 0 {
+		return
+	}
+	pid := m.visibleProc[m.cursor].PID
+	if m.marked[pid] {
+		delete(m.marked, pid)
+	} else {
+		m.marked[pid] = true
+	}
+	m.moveCursor(1)
+}
+
+// markAllVisible marks every row that passes the current filter.
+func (m *Model) markAllVisible() {
+	for _, p := range m.visibleProc {
+		m.marked[p.PID] = true
+	}
+}
+
+// invertMarks flips the mark on every visible row. Marks on rows hidden by
+// the filter are left alone.
+func (m *Model) invertMarks() {
+	for _, p := range m.visibleProc {
+		if m.marked[p.PID] {
+			delete(m.marked, p.PID)
+		} else {
+			m.marked[p.PID] = true
+		}
+	}
+}
+
+// pruneMarked drops marks for PIDs that no longer exist, so a recycled PID
+// never inherits a mark meant for an exited process.
+func (m *Model) pruneMarked() {
+	if len(m.marked) == 0 {
+		return
+	}
+	live := make(map[int32]struct{}, len(m.allProcs))
+	for _, p := range m.allProcs {
+		live[p.PID] = struct{}{}
+	}
+	for pid := range m.marked {
+		if _, ok := live[pid]; !ok {
+			delete(m.marked, pid)
+		}
+	}
+}
+
+// actionTargets returns the rows a process action applies to: every marked
+// process (in PID order) if anything is marked, otherwise the selected row.
+func (m *Model) actionTargets() []ProcessRow {
+	if len(m.marked) > 0 {
+		var out []ProcessRow
+		for _, p := range m.allProcs {
+			if m.marked[p.PID] {
+				out = append(out, p)
+			}
+		}
+		sort.Slice(out, func(i, j int) bool { return out[i].PID < out[j].PID })
+		return out
+	}
+	if len(m.visibleProc) == 0 {
+		return nil
+	}
+	return []ProcessRow{m.visibleProc[m.cursor]}
+}
+
+// ---------------------------------------------------------------------------
+// Commands
+// ---------------------------------------------------------------------------
+
+// killProcesses sends sig to every target and collects one result per PID.
+func killProcesses(targets []ProcessRow, sig signalOption) tea.Cmd {
+	pids := make([]int32, len(targets))
+	for i, t := range targets {
+		pids[i] = t.PID
+	}
+	return func() tea.Msg {
+		results := make([]killResultMsg, 0, len(pids))
+		for _, pid := range pids {
+			p, err := process.NewProcess(pid)
+			if err == nil {
+				err = sendSignal(p, sig.Sig)
+			}
+			results = append(results, killResultMsg{PID: pid, Signal: sig.Name, Err: err})
+		}
+		return killBatchResultMsg{Signal: sig.Name, Results: results}
+	}
+}
+
+// summarizeBatch condenses a batch result into one status-bar line.
+func summarizeBatch(msg killBatchResultMsg) string {
+	failed := 0
+	for _, r := range msg.Results {
+		if r.Err != nil {
+			failed++
+		}
+	}
+	return fmt.Sprintf("sent %s to %d processes: %d ok, %d failed",
+		msg.Signal, len(msg.Results), len(msg.Results)-failed, failed)
+}
+
+// ---------------------------------------------------------------------------
+// View
+// ---------------------------------------------------------------------------
+
+// renderTargetList lists up to max kill targets, one per line.
+func (m *Model) renderTargetList(max int) string {
+	var b strings.Builder
+	for i, t := range m.killTargets {
+		if i == max {
+			b.WriteString(styleOverlayHint.Render(
+				fmt.Sprintf("    …and %d more", len(m.killTargets)-max)))
+			b.WriteString("\n")
+			break
+		}
+		b.WriteString(fmt.Sprintf("    %7d  %s  (%s)\n", t.PID, truncate(t.Name, 24), t.User))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// renderBatchResultOverlay shows the per-PID outcome of a batch signal.
+func (m *Model) renderBatchResultOverlay(base string) string {
+	if len(m.killResults) == 0 {
+		return base
+	}
+
+	// Leave room for the border, padding, title and hint.
+	max := m.termHeight - 10
+	if max < 3 {
+		max = 3
+	}
+
+	names := make(map[int32]string, len(m.allProcs))
+	for _, p := range m.allProcs {
+		names[p.PID] = p.Name
+	}
+
+	var b strings.Builder
+	for i, r := range m.killResults {
+		if i == max {
+			b.WriteString(styleOverlayHint.Render(
+				fmt.Sprintf("    …and %d more", len(m.killResults)-max)))
+			b.WriteString("\n")
+			break
+		}
+		name := r.Name
+		if name == "" {
+			name = names[r.PID]
+		}
+		line := fmt.Sprintf("%7d  %s", r.PID, truncate(name, 24))
+		switch {
+		case r.Err != nil:
+			b.WriteString("  " + styleStatusError.Render("✗ "+line+"  "+r.Err.Error()))
+		case r.Survived:
+			b.WriteString("  " + styleStatusError.Render("! "+line+"  still running"))
+		default:
+			b.WriteString("  " + styleCursor.Render("✓") + " " + line)
+		}
+		b.WriteString("\n")
+	}
+
+	content := styleOverlayTitle.Render(m.killResults[0].Signal+" results") + "\n\n" +
+		b.String() + "\n" +
+		"  " + styleOverlayHint.Render("Press any key to close")
+
+	box := styleOverlayBorder.Render(content)
+
+	return lipgloss.Place(
+		m.termWidth, m.termHeight,
+		lipgloss.Center, lipgloss.Center,
+		box,
+		lipgloss.WithWhitespaceChars(" "),
+		lipgloss.WithWhitespaceForeground(lipgloss.Color("0")),
+	)
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"syscall"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// signalOption is one entry in the signal picker overlay.
+type signalOption struct {
+	Name string
+	Sig  syscall.Signal
+	Desc string
+}
+
+// defaultSignal is the picker's initial selection: the first entry in
+// signalOptions (SIGTERM where available).
+const defaultSignal = 0
+
+// sendSignal delivers sig to p. SIGKILL goes through p.Kill() so that it
+// maps to TerminateProcess on Windows, where arbitrary signals don't exist.
+func sendSignal(p *process.Process, sig syscall.Signal) error {
+	if sig == syscall.SIGKILL {
+		return p.Kill()
+	}
+	return p.SendSignal(sig)
+}
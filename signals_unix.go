@@ -0,0 +1,18 @@
+//go:build !windows
+
+package main
+
+import "syscall"
+
+// signalOptions lists the signals offered by the picker, gentlest first.
+var signalOptions = []signalOption{
+	{"SIGTERM", syscall.SIGTERM, "ask the process to exit cleanly"},
+	{"SIGINT", syscall.SIGINT, "interrupt, as if Ctrl+C was pressed"},
+	{"SIGHUP", syscall.SIGHUP, "hang up — many daemons reload config"},
+	{"SIGQUIT", syscall.SIGQUIT, "quit and dump core"},
+	{"SIGUSR1", syscall.SIGUSR1, "user-defined signal 1"},
+	{"SIGUSR2", syscall.SIGUSR2, "user-defined signal 2"},
+	{"SIGSTOP", syscall.SIGSTOP, "suspend (cannot be caught)"},
+	{"SIGCONT", syscall.SIGCONT, "resume a stopped process"},
+	{"SIGKILL", syscall.SIGKILL, "terminate immediately (cannot be caught)"},
+}
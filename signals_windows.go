@@ -0,0 +1,11 @@
+//go:build windows
+
+package main
+
+import "syscall"
+
+// signalOptions lists the signals offered by the picker. Windows has no
+// POSIX signal delivery, so only forced termination is available.
+var signalOptions = []signalOption{
+	{"SIGKILL", syscall.SIGKILL, "force-terminate (TerminateProcess)"},
+}
@@ -13,117 +13,307 @@ var (
 	colorGreen    = lipgloss.Color("82")  // bright green
 	colorWhite    = lipgloss.Color("255")
 	colorBg       = lipgloss.Color("235") // header background
+	colorMarked   = lipgloss.Color("220") // amber
+	colorZombie   = lipgloss.Color("201") // magenta — state Z
+	colorBlocked  = lipgloss.Color("208") // orange — state D
+)
+
+// Styles are derived from the colours above; buildStyles recreates them
+// after a config file changes the theme.
+var (
+	styleHeader             lipgloss.Style
+	styleHeaderLabel        lipgloss.Style
+	styleHeaderValue        lipgloss.Style
+	stylePaused             lipgloss.Style
+	styleBarLow             lipgloss.Style
+	styleBarMid             lipgloss.Style
+	styleBarHigh            lipgloss.Style
+	styleMemUsed            lipgloss.Style
+	styleMemBuffers         lipgloss.Style
+	styleMemCached          lipgloss.Style
+	styleBarEmpty           lipgloss.Style
+	styleColHeader          lipgloss.Style
+	styleColHeaderSelected  lipgloss.Style
+	styleRowNormal          lipgloss.Style
+	styleRowSelected        lipgloss.Style
+	styleRowHighCPU         lipgloss.Style
+	styleRowHighCPUSelected lipgloss.Style
+	styleRowMarked          lipgloss.Style
+	styleRowMarkedSelected  lipgloss.Style
+	styleRowStopped         lipgloss.Style
+	styleRowStoppedSelected lipgloss.Style
+	styleRowZombie          lipgloss.Style
+	styleRowZombieSelected  lipgloss.Style
+	styleRowBlocked         lipgloss.Style
+	styleRowBlockedSelected lipgloss.Style
+	styleStateZombie        lipgloss.Style
+	styleStateBlocked       lipgloss.Style
+	styleCellWarn           lipgloss.Style
+	styleMarkGlyph          lipgloss.Style
+	styleDiffStarted        lipgloss.Style
+	styleRowExited          lipgloss.Style
+	styleCursor             lipgloss.Style
+	styleFilterLabel        lipgloss.Style
+	styleFilterHint         lipgloss.Style
+	styleFilterError        lipgloss.Style
+	styleFilterMatch        lipgloss.Style
+	styleOverlayBorder      lipgloss.Style
+	styleOverlayTitle       lipgloss.Style
+	styleOverlayHint        lipgloss.Style
+	styleOverlaySelected    lipgloss.Style
+	styleStatusBar          lipgloss.Style
+	styleStatusError        lipgloss.Style
+	styleStoppedBadge       lipgloss.Style
+	styleAlertBadge         lipgloss.Style
+	styleHelpTitle          lipgloss.Style
+	styleHelpSection        lipgloss.Style
+	styleHelpKey            lipgloss.Style
+	styleHelpDesc           lipgloss.Style
+	styleBorder             lipgloss.Style
+)
+
+func init() { buildStyles() }
 
+func buildStyles() {
 	// -------------------------------------------------------------------------
 	// Header panel
 	// -------------------------------------------------------------------------
 	styleHeader = lipgloss.NewStyle().
-			Background(colorBg).
-			Foreground(colorWhite).
-			Bold(true).
-			PaddingLeft(1).
-			PaddingRight(1)
+		Background(colorBg).
+		Foreground(colorWhite).
+		Bold(true).
+		PaddingLeft(1).
+		PaddingRight(1)
 
 	styleHeaderLabel = lipgloss.NewStyle().
-				Foreground(colorAccent).
-				Background(colorBg).
-				Bold(true)
+		Foreground(colorAccent).
+		Background(colorBg).
+		Bold(true)
 
 	styleHeaderValue = lipgloss.NewStyle().
-				Foreground(colorWhite).
-				Background(colorBg)
+		Foreground(colorWhite).
+		Background(colorBg)
+
+	// Frozen-snapshot indicator
+	stylePaused = lipgloss.NewStyle().
+		Foreground(colorBg).
+		Background(colorMarked).
+		Bold(true)
+
+	// Utilisation bars: green below 50%, amber below 80%, red above
+	styleBarLow = lipgloss.NewStyle().
+		Foreground(colorGreen).
+		Background(colorBg)
+
+	styleBarMid = lipgloss.NewStyle().
+		Foreground(colorMarked).
+		Background(colorBg)
+
+	styleBarHigh = lipgloss.NewStyle().
+		Foreground(colorHighCPU).
+		Background(colorBg)
+
+	// Z and D counts in the header
+	styleStateZombie = lipgloss.NewStyle().
+		Foreground(colorZombie).
+		Background(colorBg).
+		Bold(true)
+
+	styleStateBlocked = lipgloss.NewStyle().
+		Foreground(colorBlocked).
+		Background(colorBg).
+		Bold(true)
+
+	// Memory bar segments
+	styleMemUsed = lipgloss.NewStyle().
+		Foreground(colorGreen).
+		Background(colorBg)
+
+	styleMemBuffers = lipgloss.NewStyle().
+		Foreground(colorAccent).
+		Background(colorBg)
+
+	styleMemCached = lipgloss.NewStyle().
+		Foreground(colorMarked).
+		Background(colorBg)
+
+	styleBarEmpty = lipgloss.NewStyle().
+		Foreground(colorMuted).
+		Background(colorBg)
 
 	// -------------------------------------------------------------------------
 	// Table header row
 	// -------------------------------------------------------------------------
 	styleColHeader = lipgloss.NewStyle().
-			Foreground(colorAccent).
-			Bold(true)
+		Foreground(colorAccent).
+		Bold(true)
 
 	styleColHeaderSelected = lipgloss.NewStyle().
-				Foreground(colorBg).
-				Background(colorAccent).
-				Bold(true)
+		Foreground(colorBg).
+		Background(colorAccent).
+		Bold(true)
 
 	// -------------------------------------------------------------------------
 	// Table rows
 	// -------------------------------------------------------------------------
 	styleRowNormal = lipgloss.NewStyle().
-			Foreground(colorWhite)
+		Foreground(colorWhite)
 
 	styleRowSelected = lipgloss.NewStyle().
-				Foreground(colorWhite).
-				Background(colorSelected).
-				Bold(true)
+		Foreground(colorWhite).
+		Background(colorSelected).
+		Bold(true)
 
 	styleRowHighCPU = lipgloss.NewStyle().
-			Foreground(colorHighCPU)
+		Foreground(colorHighCPU)
 
 	styleRowHighCPUSelected = lipgloss.NewStyle().
-				Foreground(colorHighCPU).
-				Background(colorSelected).
-				Bold(true)
+		Foreground(colorHighCPU).
+		Background(colorSelected).
+		Bold(true)
+
+	styleRowMarked = lipgloss.NewStyle().
+		Foreground(colorMarked)
+
+	styleRowMarkedSelected = lipgloss.NewStyle().
+		Foreground(colorMarked).
+		Background(colorSelected).
+		Bold(true)
+
+	// Suspended (SIGSTOP / state T) processes
+	styleRowStopped = lipgloss.NewStyle().
+		Foreground(colorMuted).
+		Italic(true)
+
+	styleRowStoppedSelected = lipgloss.NewStyle().
+		Foreground(colorMuted).
+		Background(colorSelected).
+		Bold(true).
+		Italic(true)
+
+	// Zombie (Z) and uninterruptible-sleep (D) processes
+	styleRowZombie = lipgloss.NewStyle().
+		Foreground(colorZombie).
+		Bold(true)
+
+	styleRowZombieSelected = lipgloss.NewStyle().
+		Foreground(colorZombie).
+		Background(colorSelected).
+		Bold(true)
+
+	styleRowBlocked = lipgloss.NewStyle().
+		Foreground(colorBlocked).
+		Bold(true)
+
+	styleRowBlockedSelected = lipgloss.NewStyle().
+		Foreground(colorBlocked).
+		Background(colorSelected).
+		Bold(true)
+
+	// A single cell past a warning threshold (e.g. FDs near the limit)
+	styleCellWarn = lipgloss.NewStyle().
+		Foreground(colorHighCPU).
+		Bold(true)
+
+	styleMarkGlyph = lipgloss.NewStyle().
+		Foreground(colorMarked).
+		Bold(true)
+
+	// Baseline diff: + before processes started since the baseline, and
+	// dimmed rows for the ones that have exited
+	styleDiffStarted = lipgloss.NewStyle().
+		Foreground(colorGreen).
+		Bold(true)
+
+	styleRowExited = lipgloss.NewStyle().
+		Foreground(colorMuted).
+		Strikethrough(true)
 
 	// Cursor indicator (▶ / space)
 	styleCursor = lipgloss.NewStyle().
-			Foreground(colorGreen).
-			Bold(true)
+		Foreground(colorGreen).
+		Bold(true)
 
 	// -------------------------------------------------------------------------
 	// Filter bar
 	// -------------------------------------------------------------------------
 	styleFilterLabel = lipgloss.NewStyle().
-				Foreground(colorAccent).
-				Bold(true)
+		Foreground(colorAccent).
+		Bold(true)
 
 	styleFilterHint = lipgloss.NewStyle().
-			Foreground(colorMuted)
+		Foreground(colorMuted)
+
+	// Query parse error, shown in place of the hint
+	styleFilterError = lipgloss.NewStyle().
+		Foreground(colorHighCPU)
+
+	// Characters matched by the filter, highlighted in the NAME cell
+	styleFilterMatch = lipgloss.NewStyle().
+		Foreground(colorMarked).
+		Bold(true).
+		Underline(true)
 
 	// -------------------------------------------------------------------------
-	// Kill overlay border + text
+	// Signal overlay border + text
 	// -------------------------------------------------------------------------
 	styleOverlayBorder = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(colorHighCPU).
-				Padding(1, 3)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorHighCPU).
+		Padding(1, 3)
 
 	styleOverlayTitle = lipgloss.NewStyle().
-				Foreground(colorHighCPU).
-				Bold(true)
+		Foreground(colorHighCPU).
+		Bold(true)
 
 	styleOverlayHint = lipgloss.NewStyle().
-				Foreground(colorMuted)
+		Foreground(colorMuted)
+
+	styleOverlaySelected = lipgloss.NewStyle().
+		Foreground(colorWhite).
+		Background(colorSelected).
+		Bold(true)
 
 	// -------------------------------------------------------------------------
 	// Status bar (bottom)
 	// -------------------------------------------------------------------------
 	styleStatusBar = lipgloss.NewStyle().
-			Foreground(colorMuted)
+		Foreground(colorMuted)
 
 	styleStatusError = lipgloss.NewStyle().
-				Foreground(colorHighCPU)
+		Foreground(colorHighCPU)
+
+	styleStoppedBadge = lipgloss.NewStyle().
+		Foreground(colorBg).
+		Background(colorMuted).
+		Bold(true)
+
+	styleAlertBadge = lipgloss.NewStyle().
+		Foreground(colorWhite).
+		Background(colorHighCPU).
+		Bold(true)
 
 	// -------------------------------------------------------------------------
 	// Help screen
 	// -------------------------------------------------------------------------
 	styleHelpTitle = lipgloss.NewStyle().
-			Foreground(colorAccent).
-			Bold(true)
+		Foreground(colorAccent).
+		Bold(true)
 
 	styleHelpSection = lipgloss.NewStyle().
-				Foreground(colorAccent).
-				Bold(true)
+		Foreground(colorAccent).
+		Bold(true)
 
 	styleHelpKey = lipgloss.NewStyle().
-			Foreground(colorGreen).
-			Bold(true)
+		Foreground(colorGreen).
+		Bold(true)
 
 	styleHelpDesc = lipgloss.NewStyle().
-			Foreground(colorWhite)
+		Foreground(colorWhite)
 
 	// -------------------------------------------------------------------------
 	// Border / separator
 	// -------------------------------------------------------------------------
 	styleBorder = lipgloss.NewStyle().
-			Foreground(colorMuted)
-)
+		Foreground(colorMuted)
+}
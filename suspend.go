@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// signalByName looks up an entry of signalOptions.
+func signalByName(name string) (signalOption, bool) {
+	for _, s := range signalOptions {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return signalOption{}, false
+}
+
+// signalTargets sends the named signal straight to the action targets,
+// without going through the picker. Results come back as the usual
+// killResultMsg / killBatchResultMsg.
+func (m *Model) signalTargets(name string) tea.Cmd {
+	sig, ok := signalByName(name)
+	if !ok {
+		m.statusMsg = fmt.Sprintf("%s is not supported on %s", name, runtime.GOOS)
+		return nil
+	}
+	targets := m.actionTargets()
+	switch len(targets) {
+	case 0:
+		return nil
+	case 1:
+		return killProcess(targets[0].PID, sig)
+	default:
+		return killProcesses(targets, sig)
+	}
+}
+
+// trackStopped records which PIDs gomon has suspended, from the outcome of
+// any signal sent — the dedicated keys or the picker.
+func (m *Model) trackStopped(r killResultMsg) {
+	if r.Err != nil {
+		return
+	}
+	switch r.Signal {
+	case "SIGSTOP":
+		m.stopped[r.PID] = true
+	case "SIGCONT", "SIGKILL":
+		delete(m.stopped, r.PID)
+	}
+}
+
+// pruneStopped forgets PIDs that have exited, or that something else has
+// resumed (their state is known and no longer T).
+func (m *Model) pruneStopped() {
+	if len(m.stopped) == 0 {
+		return
+	}
+	state := make(map[int32]string, len(m.allProcs))
+	for _, p := range m.allProcs {
+		state[p.PID] = p.State
+	}
+	for pid := range m.stopped {
+		st, ok := state[pid]
+		if !ok || (st != "" && st != "T") {
+			delete(m.stopped, pid)
+		}
+	}
+}
+
+// isStopped reports whether a row should be drawn as suspended.
+func (m *Model) isStopped(r ProcessRow) bool {
+	return m.stopped[r.PID] || r.State == "T"
+}
+
+// renderStoppedIndicator lists the PIDs gomon has suspended, for the
+// status line. Empty when there are none.
+func (m *Model) renderStoppedIndicator() string {
+	if len(m.stopped) == 0 {
+		return ""
+	}
+	pids := make([]int, 0, len(m.stopped))
+	for pid := range m.stopped {
+		pids = append(pids, int(pid))
+	}
+	sort.Ints(pids)
+	const max = 5
+	strs := make([]string, 0, max)
+	for i, pid := range pids {
+		if i == max {
+			strs = append(strs, fmt.Sprintf("+%d", len(pids)-max))
+			break
+		}
+		strs = append(strs, fmt.Sprint(pid))
+	}
+	return styleStoppedBadge.Render(fmt.Sprintf(" ⏸ stopped: %s ", strings.Join(strs, " ")))
+}
@@ -3,12 +3,18 @@ package main
 import (
 	"fmt"
 
+	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/load"
 	"github.com/shirou/gopsutil/v3/mem"
 )
 
-// CollectSysStats gathers hostname, human-readable uptime, and RAM usage.
+// CollectSysStats gathers hostname, human-readable uptime, RAM and swap
+// breakdown, CPU utilisation, load averages and task counts.
 // Returned as a sysStatsMsg ready to be dispatched as a tea.Msg.
+//
+// Like process CPU%, cpu.Percent(0, …) measures the delta since its previous
+// call, so CPU figures are zero on the first tick.
 func CollectSysStats() sysStatsMsg {
 	msg := sysStatsMsg{}
 
@@ -20,6 +26,7 @@ func CollectSysStats() sysStatsMsg {
 	}
 	msg.Hostname = info.Hostname
 	msg.Uptime = formatUptime(info.Uptime)
+	msg.UptimeSecs = info.Uptime
 
 	// RAM
 	vmStat, err := mem.VirtualMemory()
@@ -30,6 +37,35 @@ func CollectSysStats() sysStatsMsg {
 	const gb = 1 << 30
 	msg.MemUsed = float64(vmStat.Used) / gb
 	msg.MemTotal = float64(vmStat.Total) / gb
+	msg.MemAvail = float64(vmStat.Available) / gb
+	msg.MemBuffers = float64(vmStat.Buffers) / gb
+	msg.MemCached = float64(vmStat.Cached) / gb
+	msg.MemDirty = float64(vmStat.Dirty) / gb
+	msg.MemWriteback = float64(vmStat.WriteBack) / gb
+
+	// Swap may legitimately be absent (Total 0); only a read failure is skipped.
+	if swap, err := mem.SwapMemory(); err == nil {
+		msg.SwapUsed = float64(swap.Used) / gb
+		msg.SwapTotal = float64(swap.Total) / gb
+	}
+
+	// CPU — failures here are cosmetic, so leave the fields zeroed.
+	if total, err := cpu.Percent(0, false); err == nil && len(total) > 0 {
+		msg.CPUTotal = total[0]
+	}
+	if perCore, err := cpu.Percent(0, true); err == nil {
+		msg.CPUPerCore = perCore
+	}
+
+	// Load average and task counts
+	if avg, err := load.Avg(); err == nil {
+		msg.Load1, msg.Load5, msg.Load15 = avg.Load1, avg.Load5, avg.Load15
+		msg.HasLoad = true
+	}
+	if misc, err := load.Misc(); err == nil {
+		msg.TasksRun = misc.ProcsRunning
+		msg.TasksTotal = misc.ProcsTotal
+	}
 
 	return msg
 }
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// treeLine carries the per-row decoration needed to draw one process in tree
+// mode. It is kept parallel to Model.visibleProc.
+type treeLine struct {
+	prefix   string // branch glyphs drawn before the name ("│  ├─ ")
+	depth    int
+	children int  // number of direct children in the (filtered) set
+	hidden   int  // descendants hidden because this node is collapsed
+	folded   bool // node is collapsed
+}
+
+// buildTree arranges rows into a depth-first parent/child ordering.
+// Rows whose parent is not present in rows become roots. Siblings are
+// ordered with less, so the current sort column applies within each group.
+// Descendants of PIDs in collapsed are omitted from the result.
+func buildTree(rows []ProcessRow, collapsed map[int32]bool, less func(a, b ProcessRow) bool) ([]ProcessRow, []treeLine) {
+	present := make(map[int32]struct{}, len(rows))
+	for _, r := range rows {
+		present[r.PID] = struct{}{}
+	}
+
+	children := make(map[int32][]ProcessRow, len(rows))
+	var roots []ProcessRow
+	for _, r := range rows {
+		_, hasParent := present[r.PPID]
+		if !hasParent || r.PPID == r.PID {
+			roots = append(roots, r)
+			continue
+		}
+		children[r.PPID] = append(children[r.PPID], r)
+	}
+
+	sortGroup := func(g []ProcessRow) {
+		sort.SliceStable(g, func(i, j int) bool { return less(g[i], g[j]) })
+	}
+	sortGroup(roots)
+	for _, g := range children {
+		sortGroup(g)
+	}
+
+	out := make([]ProcessRow, 0, len(rows))
+	lines := make([]treeLine, 0, len(rows))
+	visited := make(map[int32]bool, len(rows))
+
+	// countDescendants is only needed for collapsed nodes, so compute lazily.
+	var countDescendants func(pid int32, seen map[int32]bool) int
+	countDescendants = func(pid int32, seen map[int32]bool) int {
+		n := 0
+		for _, c := range children[pid] {
+			if seen[c.PID] {
+				continue
+			}
+			seen[c.PID] = true
+			n += 1 + countDescendants(c.PID, seen)
+		}
+		return n
+	}
+
+	var walk func(r ProcessRow, indent string, last bool, depth int)
+	walk = func(r ProcessRow, indent string, last bool, depth int) {
+		// PID reuse can in theory produce a cycle; never visit a row twice.
+		if visited[r.PID] {
+			return
+		}
+		visited[r.PID] = true
+
+		prefix := ""
+		childIndent := ""
+		if depth > 0 {
+			if last {
+				prefix = indent + "└─ "
+				childIndent = indent + "   "
+			} else {
+				prefix = indent + "├─ "
+				childIndent = indent + "│  "
+			}
+		}
+
+		kids := children[r.PID]
+		line := treeLine{prefix: prefix, depth: depth, children: len(kids)}
+		if collapsed[r.PID] && len(kids) > 0 {
+			line.folded = true
+			line.hidden = countDescendants(r.PID, map[int32]bool{r.PID: true})
+		}
+		out = append(out, r)
+		lines = append(lines, line)
+
+		if line.folded {
+			return
+		}
+		for i, c := range kids {
+			walk(c, childIndent, i == len(kids)-1, depth+1)
+		}
+	}
+
+	for _, r := range roots {
+		walk(r, "", true, 0)
+	}
+
+	// Anything left unvisited is part of a parent cycle with no root;
+	// append it flat rather than dropping it from the view.
+	for _, r := range rows {
+		if !visited[r.PID] {
+			walk(r, "", true, 0)
+		}
+	}
+
+	return out, lines
+}
+
+// parentIndex returns the index in visibleProc of the row's tree parent,
+// or -1 if the row at idx is a root.
+func (m *Model) parentIndex(idx int) int {
+	if idx < 0 || idx >= len(m.treeLines) {
+		return -1
+	}
+	depth := m.treeLines[idx].depth
+	for i := idx - 1; i >= 0; i-- {
+		if m.treeLines[i].depth < depth {
+			return i
+		}
+	}
+	return -1
+}
+
+// collapseSelected folds the subtree under the cursor. On a leaf (or an
+// already-folded node) it folds the parent instead and jumps to it.
+func (m *Model) collapseSelected() {
+	if !m.treeMode || len(m.visibleProc) == 0 {
+		return
+	}
+	line := m.treeLines[m.cursor]
+	target := m.cursor
+	if line.children == 0 || line.folded {
+		target = m.parentIndex(m.cursor)
+		if target < 0 {
+			return
+		}
+	}
+	m.collapsed[m.visibleProc[target].PID] = true
+	pid := m.visibleProc[target].PID
+	m.applyFilterAndSort()
+	m.selectPID(pid)
+}
+
+// expandSelected unfolds the subtree under the cursor.
+func (m *Model) expandSelected() {
+	if !m.treeMode || len(m.visibleProc) == 0 {
+		return
+	}
+	pid := m.visibleProc[m.cursor].PID
+	if !m.collapsed[pid] {
+		return
+	}
+	delete(m.collapsed, pid)
+	m.applyFilterAndSort()
+	m.selectPID(pid)
+}
+
+// selectPID moves the cursor to the row with the given PID, if visible.
+func (m *Model) selectPID(pid int32) {
+	for i, p := range m.visibleProc {
+		if p.PID == pid {
+			m.cursor = i
+			break
+		}
+	}
+	m.clampCursor()
+}
+
+// treePrefixLen is the rune width of the connector drawn before the name of
+// visible row idx (0 outside tree mode).
+func (m *Model) treePrefixLen(idx int) int {
+	if !m.treeMode || idx >= len(m.treeLines) {
+		return 0
+	}
+	return utf8.RuneCountInString(m.treeLines[idx].prefix)
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/shirou/gopsutil/v3/net"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// Run `go test -run Golden -update` to rewrite testdata/golden after an
+// intended change to the views, then review the diff.
+var updateGolden = flag.Bool("update", false, "rewrite golden files")
+
+func TestMain(m *testing.M) {
+	// Plain text keeps the golden files readable and independent of the
+	// terminal the tests run in.
+	lipgloss.SetColorProfile(termenv.Ascii)
+	os.Exit(m.Run())
+}
+
+// goldenSys is the system sample every golden view starts from.
+var goldenSys = sysStatsMsg{
+	Hostname:   "testhost",
+	Uptime:     "3d 4h 5m",
+	UptimeSecs: 273900,
+	MemUsed:    6.2,
+	MemTotal:   16,
+	MemAvail:   9.1,
+	MemBuffers: 0.4,
+	MemCached:  3.3,
+	SwapUsed:   0.5,
+	SwapTotal:  4,
+	CPUTotal:   37.5,
+	CPUPerCore: []float64{80, 40, 20, 10},
+	Load1:      1.25,
+	Load5:      0.9,
+	Load15:     0.75,
+	HasLoad:    true,
+	TasksRun:   2,
+	TasksTotal: 7,
+}
+
+// goldenProcs is two samples of a small process tree covering the states
+// the table colours: running, sleeping, uninterruptible, zombie, stopped.
+var goldenProcs = [][]ProcessRow{
+	{
+		{PID: 1, PPID: 0, Name: "init", CPU: 0.1, MemMB: 12, Threads: 1, User: "root", State: "S"},
+		{PID: 120, PPID: 1, Name: "sshd", CPU: 0, MemMB: 8.5, Threads: 1, User: "root", State: "S"},
+		{PID: 300, PPID: 1, Name: "postgres", CPU: 12.5, MemMB: 512, Threads: 6, User: "postgres", State: "R", Ports: []uint32{5432}},
+		{PID: 301, PPID: 300, Name: "postgres: writer", CPU: 3, MemMB: 64, Threads: 1, User: "postgres", State: "D"},
+		{PID: 400, PPID: 120, Name: "bash", CPU: 0, MemMB: 4.2, Threads: 1, User: "alice", State: "S"},
+		{PID: 401, PPID: 400, Name: "make", CPU: 0, MemMB: 0, Threads: 1, User: "alice", State: "Z"},
+		{PID: 402, PPID: 400, Name: "vim", CPU: 0, MemMB: 22, Threads: 1, User: "alice", State: "T"},
+	},
+	{
+		{PID: 1, PPID: 0, Name: "init", CPU: 0.2, MemMB: 12, Threads: 1, User: "root", State: "S"},
+		{PID: 120, PPID: 1, Name: "sshd", CPU: 0.5, MemMB: 8.5, Threads: 1, User: "root", State: "S"},
+		{PID: 300, PPID: 1, Name: "postgres", CPU: 45, MemMB: 540, Threads: 6, User: "postgres", State: "R", Ports: []uint32{5432}},
+		{PID: 301, PPID: 300, Name: "postgres: writer", CPU: 9, MemMB: 64, Threads: 1, User: "postgres", State: "D"},
+		{PID: 400, PPID: 120, Name: "bash", CPU: 0, MemMB: 4.2, Threads: 1, User: "alice", State: "S"},
+		{PID: 401, PPID: 400, Name: "make", CPU: 0, MemMB: 0, Threads: 1, User: "alice", State: "Z"},
+		{PID: 402, PPID: 400, Name: "vim", CPU: 0, MemMB: 22, Threads: 1, User: "alice", State: "T"},
+	},
+}
+
+// goldenLater is a sample taken after goldenProcs: vim has exited, a
+// compiler has started and postgres has grown.
+var goldenLater = []ProcessRow{
+	{PID: 1, PPID: 0, Name: "init", CPU: 0.2, MemMB: 12, Threads: 1, User: "root", State: "S"},
+	{PID: 120, PPID: 1, Name: "sshd", CPU: 0.5, MemMB: 8.5, Threads: 1, User: "root", State: "S"},
+	{PID: 300, PPID: 1, Name: "postgres", CPU: 20, MemMB: 700, Threads: 8, User: "postgres", State: "R", Ports: []uint32{5432}},
+	{PID: 301, PPID: 300, Name: "postgres: writer", CPU: 9, MemMB: 64, Threads: 1, User: "postgres", State: "D"},
+	{PID: 400, PPID: 120, Name: "bash", CPU: 0, MemMB: 4.2, Threads: 1, User: "alice", State: "S"},
+	{PID: 401, PPID: 400, Name: "make", CPU: 0, MemMB: 0, Threads: 1, User: "alice", State: "Z"},
+	{PID: 500, PPID: 400, Name: "cc1", CPU: 98, MemMB: 120, Threads: 1, User: "alice", State: "R"},
+}
+
+// goldenModel returns a model that has been sized and has taken both
+// scripted samples.
+func goldenModel(t *testing.T, width, height int) Model {
+	t.Helper()
+	fc := &fakeCollector{sys: []sysStatsMsg{goldenSys}, procs: goldenProcs}
+	m := NewModel(fc)
+	next, _ := m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	m = runCmd(next.(Model), m.Init())
+	next, cmd := m.Update(tickMsg{gen: m.tickGen})
+	return runCmd(next.(Model), cmd)
+}
+
+// send delivers msg to m and discards the command it returns. Views that
+// would query the live system are opened this way and then fed a fixed
+// reply, so the output does not depend on the machine running the test.
+func send(m Model, msg tea.Msg) Model {
+	next, _ := m.Update(msg)
+	return next.(Model)
+}
+
+// goldenAlerts configures two rules and runs a scripted sample through
+// them at fixed times: postgres fires and resolves, cc1 stays firing.
+func goldenAlerts(t *testing.T, m Model) Model {
+	m.alerts = newAlerter([]*alertRule{
+		mustAlertRule(t, alertRuleConfig{Name: "busy", Match: "cpu>40", For: 10 * time.Second}),
+		mustAlertRule(t, alertRuleConfig{Name: "low memory", System: "avail<5"}),
+	})
+	t0 := time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC)
+	for i, procs := range [][]ProcessRow{goldenProcs[1], goldenProcs[1], goldenLater, goldenLater} {
+		m.alerts.add(m.alerts.checkProcs(procs, t0.Add(time.Duration(i)*10*time.Second), "testhost"))
+	}
+	return m
+}
+
+func keyMsg(key string) tea.Msg {
+	switch key {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
+func TestGoldenViews(t *testing.T) {
+	sizes := []struct{ w, h int }{{100, 30}, {60, 20}}
+	views := []struct {
+		name string
+		mode AppMode
+		skip bool
+		open func(m Model) Model
+	}{
+		{name: "normal", mode: ModeNormal, open: func(m Model) Model { return m }},
+		{name: "tree", mode: ModeNormal, open: func(m Model) Model {
+			return send(m, keyMsg(keyTree))
+		}},
+		{name: "paused", mode: ModeNormal, open: func(m Model) Model {
+			return send(m, keyMsg(keyPause))
+		}},
+		{name: "filter", mode: ModeFilter, open: func(m Model) Model {
+			m = send(m, keyMsg(keyFilter))
+			for _, r := range "user:alice" {
+				m = send(m, keyMsg(string(r)))
+			}
+			return m
+		}},
+		// The picker lists the platform's signals.
+		{name: "signal", mode: ModeSignal, skip: runtime.GOOS != "linux", open: func(m Model) Model {
+			return send(m, keyMsg(keyKill))
+		}},
+		{name: "signal_result", mode: ModeSignalResult, open: func(m Model) Model {
+			return send(m, killBatchResultMsg{Signal: "SIGTERM", Results: []killResultMsg{
+				{PID: 400, Name: "bash", Signal: "SIGTERM"},
+				{PID: 1, Name: "init", Signal: "SIGTERM", Err: errors.New("operation not permitted")},
+			}})
+		}},
+		{name: "help", mode: ModeHelp, open: func(m Model) Model {
+			return send(m, keyMsg(keyHelp))
+		}},
+		{name: "help_scrolled", mode: ModeHelp, open: func(m Model) Model {
+			m = send(m, keyMsg(keyHelp))
+			for i := 0; i < 12; i++ {
+				m = send(m, keyMsg(keyVimDown))
+			}
+			return m
+		}},
+		{name: "detail", mode: ModeDetail, open: func(m Model) Model {
+			m = send(m, keyMsg("enter"))
+			return send(m, processDetailMsg{PID: m.detailPID, Detail: &ProcessDetail{
+				PID: m.detailPID, PPID: 1, ParentName: "init", Name: "postgres", User: "postgres",
+				Cmdline: "/usr/lib/postgresql/16/bin/postgres -D /var/lib/postgresql/16/main",
+				Exe:     "/usr/lib/postgresql/16/bin/postgres", Cwd: "/var/lib/postgresql/16/main",
+				Status: "running", NumFDs: 24,
+				Rlimits: []process.RlimitStat{{Resource: process.RLIMIT_NOFILE, Soft: 1024, Hard: 4096}},
+				Environ: []string{"LANG=C.UTF-8", "PGDATA=/var/lib/postgresql/16/main"},
+				IO:      process.IOCountersStat{ReadCount: 1200, WriteCount: 300, ReadBytes: 48 << 20, WriteBytes: 3 << 20},
+			}})
+		}},
+		{name: "columns", mode: ModeColumns, open: func(m Model) Model {
+			return send(m, keyMsg(keyColumns))
+		}},
+		{name: "renice", mode: ModeRenice, open: func(m Model) Model {
+			m = send(m, keyMsg(keyRenice))
+			return send(m, niceValuesMsg{m.visibleProc[m.cursor].PID: 0})
+		}},
+		{name: "connections", mode: ModeConnections, open: func(m Model) Model {
+			m = send(m, keyMsg(keyConns))
+			return send(m, connsMsg{PID: m.connsPID, Conns: []net.ConnectionStat{
+				{Family: syscall.AF_INET, Type: syscall.SOCK_STREAM, Status: "LISTEN",
+					Laddr: net.Addr{IP: "0.0.0.0", Port: 5432}},
+				{Family: syscall.AF_INET6, Type: syscall.SOCK_STREAM, Status: "LISTEN",
+					Laddr: net.Addr{IP: "::", Port: 5432}},
+				{Family: syscall.AF_INET, Type: syscall.SOCK_STREAM, Status: "ESTABLISHED",
+					Laddr: net.Addr{IP: "127.0.0.1", Port: 5432}, Raddr: net.Addr{IP: "127.0.0.1", Port: 51234}},
+				{Family: syscall.AF_UNIX, Type: syscall.SOCK_STREAM, Status: "NONE",
+					Laddr: net.Addr{IP: "/run/postgresql/.s.PGSQL.5432"}},
+			}})
+		}},
+		{name: "baseline", mode: ModeNormal, open: func(m Model) Model {
+			m = send(m, keyMsg(keyBaseline))
+			m.baselineAt = time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC)
+			m = send(m, processesMsg{Procs: goldenLater})
+			return send(m, keyMsg("down")) // off the new row, so its + shows
+		}},
+		{name: "diff", mode: ModeDiff, open: func(m Model) Model {
+			m = send(m, keyMsg(keyBaseline))
+			m.baselineAt = time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC)
+			m = send(m, processesMsg{Procs: goldenLater})
+			return send(m, keyMsg(keyBaseDiff))
+		}},
+		{name: "alert_banner", mode: ModeNormal, open: func(m Model) Model {
+			return goldenAlerts(t, m)
+		}},
+		{name: "alerts", mode: ModeAlerts, open: func(m Model) Model {
+			return send(goldenAlerts(t, m), keyMsg(keyAlerts))
+		}},
+		{name: "files", mode: ModeFiles, open: func(m Model) Model {
+			m = send(m, keyMsg(keyFiles))
+			return send(m, filesMsg{PID: m.filesPID, Limit: 1024, Files: []process.OpenFilesStat{
+				{Fd: 3, Path: "/var/lib/postgresql/16/main/base/5/1259"},
+				{Fd: 4, Path: "/var/log/postgresql/postgresql-16-main.log"},
+				{Fd: 7, Path: "/dev/null"},
+			}})
+		}},
+	}
+
+	for _, sz := range sizes {
+		for _, v := range views {
+			name := fmt.Sprintf("%s_%dx%d", v.name, sz.w, sz.h)
+			t.Run(name, func(t *testing.T) {
+				if v.skip {
+					t.Skip("platform-specific view")
+				}
+				m := v.open(goldenModel(t, sz.w, sz.h))
+				if m.mode != v.mode {
+					t.Fatalf("mode = %v, want %v", m.mode, v.mode)
+				}
+				checkGolden(t, name, m.View())
+			})
+		}
+	}
+}
+
+// checkGolden compares got with testdata/golden/<name>.golden, or rewrites
+// the file when -update is set. Trailing spaces are trimmed so editors
+// don't churn the files.
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	lines := strings.Split(got, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " ")
+	}
+	got = strings.Join(lines, "\n") + "\n"
+
+	path := filepath.Join("testdata", "golden", name+".golden")
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s differs from golden file (run with -update to accept):\n--- got\n%s--- want\n%s", name, got, want)
+	}
+}